@@ -8,11 +8,13 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // fusos das etapas mesmo em imagens sem zoneinfo
 
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/handlers"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/middleware"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -47,6 +49,16 @@ func main() {
 		logrus.Fatalf("❌ Erro ao executar seed: %v", err)
 	}
 
+	// Inicializar storage de arquivos (vídeos, fotos, comprovantes)
+	if err := storage.Init(cfg); err != nil {
+		logrus.Fatalf("❌ Erro ao inicializar storage: %v", err)
+	}
+
+	// Limpeza periódica de uploads resumíveis abandonados
+	ctxLimpeza, pararLimpeza := context.WithCancel(context.Background())
+	defer pararLimpeza()
+	go handlers.LimparUploadsExpirados(ctxLimpeza, time.Hour)
+
 	// Configurar modo do Gin
 	if !cfg.IsDevelopment() {
 		gin.SetMode(gin.ReleaseMode)
@@ -117,9 +129,9 @@ func setupMiddlewares(router *gin.Engine, cfg *config.Config) {
 	// CORS
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH", "HEAD"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Metadata", "Last-Event-ID"},
+		ExposeHeaders:    []string{"Content-Length", "Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Upload-Offset", "Upload-Length", "Upload-Expires"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -191,14 +203,46 @@ func setupRoutes(router *gin.Engine) {
 		api.GET("/edicoes", handlers.ListarEdicoes)
 		api.GET("/edicoes/ativa", handlers.BuscarEdicaoAtiva)
 		api.GET("/edicoes/:id", handlers.BuscarEdicao)
+		api.GET("/edicoes/:id/imagem", handlers.BaixarImagemEdicao)
+		api.GET("/edicoes/:id/classificacao", handlers.BuscarClassificacaoEdicao)
+		api.GET("/edicoes/:id/classificacao/pdf", handlers.ExportarClassificacaoEdicaoPDF)
 
 		// Etapas (público - apenas leitura)
 		api.GET("/etapas", handlers.ListarEtapas)
 		api.GET("/etapas/:id", handlers.BuscarEtapa)
+		api.GET("/etapas/:id/regras", handlers.BuscarRegrasEtapa)
+
+		// Fotos de competidores (público)
+		api.GET("/competidores/:id/foto", handlers.BaixarFotoCompetidor)
+
+		// Arquivos do storage local (links assinados)
+		api.GET("/arquivos/*chave", handlers.ServirArquivo)
+
+		// Catálogo de penalidades (público - apenas leitura)
+		api.GET("/penalidades", handlers.ListarPenalidades)
+
+		// Catálogo de espécies da edição (público - apenas leitura)
+		api.GET("/especies", handlers.ListarEspecies)
+
+		// Categorias de competidores da edição (público - apenas leitura)
+		api.GET("/categorias", handlers.ListarCategorias)
+
+		// Regras de tamanho por espécie (público - apenas leitura)
+		api.GET("/regras-especies", handlers.ListarRegrasEspecie)
+
+		// Upload resumível (tus): descoberta de capacidades
+		api.OPTIONS("/uploads", handlers.OpcoesUpload)
+		api.OPTIONS("/uploads/:id", handlers.OpcoesUpload)
 
 		// Rankings (público)
 		api.GET("/rankings", handlers.ListarRankings)
 		api.GET("/rankings/etapa/:id", handlers.BuscarRankingEtapa)
+		api.GET("/rankings/etapa/:id/ao-vivo", handlers.AcompanharRankingEtapa) // SSE com o ranking provisório
+		api.GET("/rankings/etapa/:id/publicacoes", handlers.ListarPublicacoesRanking)
+		api.GET("/rankings/etapa/:id/pdf", handlers.ExportarRankingEtapaPDF)
+
+		// Tabela de premiação das etapas (público - apenas leitura)
+		api.GET("/premios", handlers.ListarPremios)
 
 		// ============================================
 		// ROTAS AUTENTICADAS (REQUER LOGIN)
@@ -214,10 +258,29 @@ func setupRoutes(router *gin.Engine) {
 			// Inscrições (competidores podem criar suas próprias)
 			autenticado.POST("/inscricoes", handlers.CriarInscricao)
 			autenticado.GET("/inscricoes/:id", handlers.BuscarInscricao)
+			autenticado.GET("/inscricoes/:id/cota", handlers.BuscarCotaInscricao)
+			autenticado.POST("/inscricoes/:id/comprovante", handlers.EnviarComprovante)
+			autenticado.GET("/inscricoes/:id/comprovante", handlers.BaixarComprovante)
+
+			// Foto do competidor (o próprio competidor ou organizador)
+			autenticado.POST("/competidores/:id/foto", handlers.EnviarFotoCompetidor)
 
 			// Capturas (competidores podem registrar)
 			autenticado.POST("/capturas", handlers.CriarCaptura)
 			autenticado.GET("/capturas/:id", handlers.BuscarCaptura)
+			autenticado.POST("/capturas/:id/video", handlers.EnviarVideoCaptura)
+			autenticado.GET("/capturas/:id/video", handlers.BaixarVideoCaptura)
+
+			// Recursos contra anulação ou penalidade
+			autenticado.GET("/recursos", handlers.ListarRecursos)
+			autenticado.GET("/recursos/:id", handlers.BuscarRecurso)
+			autenticado.POST("/recursos", handlers.CriarRecurso)
+
+			// Upload resumível do vídeo da captura (protocolo tus)
+			autenticado.POST("/uploads", handlers.CriarUpload)
+			autenticado.HEAD("/uploads/:id", handlers.ConsultarUpload)
+			autenticado.PATCH("/uploads/:id", handlers.EnviarParteUpload)
+			autenticado.DELETE("/uploads/:id", handlers.CancelarUpload)
 		}
 
 		// ============================================
@@ -230,11 +293,16 @@ func setupRoutes(router *gin.Engine) {
 		{
 			// Listar capturas para validação
 			fiscal.GET("/capturas", handlers.ListarCapturas)
+			fiscal.GET("/capturas/eventos", handlers.AcompanharCapturas) // WebSocket
 
 			// Validar e anular capturas
 			fiscal.PUT("/capturas/:id/validar", handlers.ValidarCaptura)
 			fiscal.PUT("/capturas/:id/anular", handlers.AnularCaptura)
 
+			// Fila de validação (reserva temporária por fiscal)
+			fiscal.POST("/fila/proxima", handlers.ProximaCapturaFila)
+			fiscal.DELETE("/capturas/:id/reserva", handlers.LiberarReservaCaptura)
+
 			// Listar inscrições
 			fiscal.GET("/inscricoes", handlers.ListarInscricoes)
 
@@ -265,10 +333,38 @@ func setupRoutes(router *gin.Engine) {
 			organizador.POST("/inscricoes/:id/confirmar-pagamento", handlers.ConfirmarPagamento)
 			organizador.POST("/inscricoes/:id/eliminar", handlers.EliminarCompetidor)
 
+			// Julgamento de recursos
+			organizador.PUT("/recursos/:id/analisar", handlers.AnalisarRecurso)
+			organizador.PUT("/recursos/:id/decidir", handlers.DecidirRecurso)
+
+			// Catálogo de penalidades da edição
+			organizador.POST("/penalidades", handlers.CriarPenalidade)
+			organizador.PUT("/penalidades/:id", handlers.AtualizarPenalidade)
+			organizador.DELETE("/penalidades/:id", handlers.DeletarPenalidade)
+			organizador.GET("/penalidades/estatisticas", handlers.EstatisticasPenalidades)
+
+			// Estratégia de pontuação da modalidade
+			organizador.PUT("/modalidades/:id/pontuacao", handlers.AtualizarPontuacaoModalidade)
+
+			// Regras de tamanho e cota por espécie
+			organizador.POST("/regras-especies", handlers.CriarRegraEspecie)
+			organizador.PUT("/regras-especies/:id", handlers.AtualizarRegraEspecie)
+			organizador.DELETE("/regras-especies/:id", handlers.DeletarRegraEspecie)
+
+			// Validação dupla: decisão sobre medições divergentes
+			organizador.PUT("/capturas/:id/resolver-divergencia", handlers.ResolverDivergencia)
+
 			// Gerenciar rankings
 			organizador.POST("/rankings/etapa/:id/gerar", handlers.GerarRanking)
+			organizador.POST("/rankings/etapa/:id/publicar", handlers.PublicarRanking)
+			organizador.PUT("/rankings/:id/pagamento", handlers.RegistrarPagamentoPremio)
 			organizador.DELETE("/rankings/:id", handlers.DeletarRanking)
 
+			// Tabela de premiação
+			organizador.POST("/premios", handlers.CriarPremio)
+			organizador.PUT("/premios/:id", handlers.AtualizarPremio)
+			organizador.DELETE("/premios/:id", handlers.DeletarPremio)
+
 			// Gerenciar competidores
 			organizador.GET("/competidores", handlers.ListarCompetidores)
 			organizador.GET("/competidores/:id", handlers.BuscarCompetidor)
@@ -289,6 +385,17 @@ func setupRoutes(router *gin.Engine) {
 			admin.POST("/edicoes", handlers.CriarEdicao)
 			admin.PUT("/edicoes/:id", handlers.AtualizarEdicao)
 			admin.DELETE("/edicoes/:id", handlers.DeletarEdicao)
+			admin.POST("/edicoes/:id/imagem", handlers.EnviarImagemEdicao)
+
+			// Catálogo de espécies da edição
+			admin.POST("/especies", handlers.CriarEspecie)
+			admin.PUT("/especies/:id", handlers.AtualizarEspecie)
+			admin.DELETE("/especies/:id", handlers.DeletarEspecie)
+
+			// Categorias de competidores (idade e sexo)
+			admin.POST("/categorias", handlers.CriarCategoria)
+			admin.PUT("/categorias/:id", handlers.AtualizarCategoria)
+			admin.DELETE("/categorias/:id", handlers.DeletarCategoria)
 
 			// Registro público de competidores
 			admin.POST("/competidores", handlers.CriarCompetidor)
@@ -43,9 +43,19 @@ type JWTConfig struct {
 
 // StorageConfig - configurações de armazenamento de arquivos
 type StorageConfig struct {
-	Type      string // local, s3, cloudinary
-	LocalPath string
-	BucketURL string
+	Type          string // local, s3
+	LocalPath     string
+	BucketURL     string // URL pública base dos links do storage local (vazio = relativa à API)
+	MaxVideoMB    int    // tamanho máximo de upload de vídeo
+	MaxArquivoMB  int    // tamanho máximo de fotos, comprovantes e imagens
+	UploadExpiraH int    // prazo (horas) para concluir um upload resumível (tus)
+	SigningSecret string // assina as URLs temporárias do storage local
+	S3Endpoint    string // ex: https://s3.amazonaws.com ou http://localhost:9000 (MinIO)
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool // endpoint/bucket/chave em vez de bucket.endpoint/chave
 }
 
 var AppConfig *Config
@@ -75,12 +85,27 @@ func Load() (*Config, error) {
 			Expiration: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
 		},
 		Storage: StorageConfig{
-			Type:      getEnv("STORAGE_TYPE", "local"),
-			LocalPath: getEnv("STORAGE_LOCAL_PATH", "./uploads"),
-			BucketURL: getEnv("STORAGE_BUCKET_URL", ""),
+			Type:          getEnv("STORAGE_TYPE", "local"),
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			BucketURL:     getEnv("STORAGE_BUCKET_URL", ""),
+			MaxVideoMB:    getEnvAsInt("STORAGE_MAX_VIDEO_MB", 500),
+			MaxArquivoMB:  getEnvAsInt("STORAGE_MAX_ARQUIVO_MB", 10),
+			UploadExpiraH: getEnvAsInt("STORAGE_UPLOAD_EXPIRA_HORAS", 24),
+			SigningSecret: getEnv("STORAGE_SIGNING_SECRET", ""),
+			S3Endpoint:    getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			S3Region:      getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("S3_BUCKET", ""),
+			S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:   getEnvAsBool("S3_PATH_STYLE", false),
 		},
 	}
 
+	// Sem segredo próprio, as URLs do storage local são assinadas com o segredo do JWT
+	if config.Storage.SigningSecret == "" {
+		config.Storage.SigningSecret = config.JWT.Secret
+	}
+
 	// Validações críticas
 	if config.Server.Environment == "production" {
 		if config.JWT.Secret == "change-me-in-production" {
@@ -147,6 +172,20 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsBool obtém variável de ambiente como booleano
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		logrus.Warnf("Não foi possível converter %s para bool, usando valor padrão: %t", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
 // setLogLevel configura o nível de log
 func setLogLevel(level string) {
 	switch level {
@@ -178,5 +217,8 @@ func (c *Config) PrintConfig() {
 	logrus.Infof("Database Host: %s:%s", c.Database.Host, c.Database.Port)
 	logrus.Infof("Database Name: %s", c.Database.DBName)
 	logrus.Infof("Storage Type: %s", c.Storage.Type)
+	if c.Storage.Type == "s3" {
+		logrus.Infof("Storage S3: %s (bucket %s)", c.Storage.S3Endpoint, c.Storage.S3Bucket)
+	}
 	logrus.Info("======================================================")
 }
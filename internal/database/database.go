@@ -94,6 +94,15 @@ func Migrate() error {
 		&models.Inscricao{},
 		&models.Captura{},
 		&models.Ranking{},
+		&models.PublicacaoRanking{},
+		&models.Premio{},
+		&models.Upload{},
+		&models.Penalidade{},
+		&models.CapturaPenalidade{},
+		&models.Recurso{},
+		&models.RegraEspecie{},
+		&models.Especie{},
+		&models.CategoriaCompetidor{},
 	)
 
 	if err != nil {
@@ -0,0 +1,88 @@
+package eventos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+)
+
+// Tipos de evento de captura
+const (
+	CapturaCriada   = "captura.criada"
+	CapturaValidada = "captura.validada"
+	CapturaAnulada  = "captura.anulada"
+	CapturaDeletada = "captura.deletada"
+)
+
+// tamanhoFila é quantos eventos um assinante pode acumular antes de ser derrubado
+const tamanhoFila = 64
+
+// Evento é uma mudança em uma captura, com a etapa a que ela pertence
+type Evento struct {
+	Tipo        string         `json:"tipo"`
+	EtapaID     string         `json:"etapa_id"`
+	EtapaStatus string         `json:"-"` // usado para filtrar o que cada assinante pode ver
+	Momento     time.Time      `json:"momento"`
+	Captura     models.Captura `json:"captura"`
+}
+
+// Assinatura recebe os eventos do hub em Eventos; o canal é fechado quando ela termina
+type Assinatura struct {
+	Eventos chan Evento
+}
+
+// Hub distribui os eventos publicados pelos handlers a todos os assinantes
+type Hub struct {
+	mu         sync.Mutex
+	assinantes map[*Assinatura]struct{}
+}
+
+// Capturas é o hub dos eventos de captura da aplicação
+var Capturas = NovoHub()
+
+// NovoHub cria um hub sem assinantes
+func NovoHub() *Hub {
+	return &Hub{assinantes: map[*Assinatura]struct{}{}}
+}
+
+// Assinar registra um novo assinante
+func (h *Hub) Assinar() *Assinatura {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	assinatura := &Assinatura{Eventos: make(chan Evento, tamanhoFila)}
+	h.assinantes[assinatura] = struct{}{}
+	return assinatura
+}
+
+// Cancelar remove o assinante e fecha o seu canal (pode ser chamado mais de uma vez)
+func (h *Hub) Cancelar(assinatura *Assinatura) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.assinantes[assinatura]; ok {
+		delete(h.assinantes, assinatura)
+		close(assinatura.Eventos)
+	}
+}
+
+// Publicar entrega o evento a todos os assinantes sem bloquear quem publica.
+// Assinante com a fila cheia é derrubado e precisa reconectar.
+func (h *Hub) Publicar(evento Evento) {
+	if evento.Momento.IsZero() {
+		evento.Momento = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for assinatura := range h.assinantes {
+		select {
+		case assinatura.Eventos <- evento:
+		default:
+			delete(h.assinantes, assinatura)
+			close(assinatura.Eventos)
+		}
+	}
+}
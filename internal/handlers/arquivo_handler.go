@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/storage"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// tempoMaximoUpload é o tempo limite de leitura para envio de arquivos grandes
+	tempoMaximoUpload = 30 * time.Minute
+
+	// validadeLinkArquivo é a validade das URLs assinadas de download
+	validadeLinkArquivo = 15 * time.Minute
+)
+
+// arquivoRecebido é um arquivo de upload já com o tipo detectado pelo conteúdo
+type arquivoRecebido struct {
+	multipart.File
+	Tamanho int64
+	Tipo    *mimetype.MIME
+}
+
+// receberArquivo lê o campo multipart, limita o tamanho e confere o tipo real do arquivo.
+// Em caso de erro a resposta já é enviada e ok retorna false.
+func receberArquivo(c *gin.Context, campo string, limiteMB int, aceito func(*mimetype.MIME) bool) (*arquivoRecebido, bool) {
+	// O ReadTimeout do servidor é curto demais para arquivos grandes; estender só nesta rota
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Now().Add(tempoMaximoUpload))
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(limiteMB)<<20)
+
+	cabecalho, err := c.FormFile(campo)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Arquivo excede o limite de %d MB", limiteMB),
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Arquivo não enviado (campo '%s')", campo),
+		})
+		return nil, false
+	}
+
+	f, err := cabecalho.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao ler arquivo enviado",
+		})
+		return nil, false
+	}
+
+	// Detectar o tipo real pelo conteúdo (não confiar no Content-Type do cliente)
+	tipo, err := mimetype.DetectReader(f)
+	if err != nil || !aceito(tipo) {
+		f.Close()
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Tipo de arquivo não permitido",
+			"tipo":  tipoDetectado(tipo),
+		})
+		return nil, false
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao ler arquivo enviado",
+		})
+		return nil, false
+	}
+
+	return &arquivoRecebido{File: f, Tamanho: cabecalho.Size, Tipo: tipo}, true
+}
+
+// gravarArquivo envia o arquivo ao storage e remove o anterior, se a chave mudou
+func gravarArquivo(ctx context.Context, r io.Reader, tamanho int64, tipo *mimetype.MIME, chave, chaveAnterior string) error {
+	if err := storage.Arquivos.Put(ctx, chave, r, tamanho, tipo.String()); err != nil {
+		return err
+	}
+
+	if chaveAnterior != "" && chaveAnterior != chave {
+		if err := storage.Arquivos.Delete(ctx, chaveAnterior); err != nil {
+			logrus.Warnf("Erro ao remover arquivo anterior %s: %v", chaveAnterior, err)
+		}
+	}
+
+	return nil
+}
+
+// redirecionarArquivo responde com um redirecionamento para a URL assinada do arquivo
+func redirecionarArquivo(c *gin.Context, chave string) {
+	if chave == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Arquivo não enviado",
+		})
+		return
+	}
+
+	url, err := storage.Arquivos.SignedURL(c.Request.Context(), chave, validadeLinkArquivo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao gerar link do arquivo",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, url)
+}
+
+// ServirArquivo entrega arquivos do storage local a partir de links assinados
+func ServirArquivo(c *gin.Context) {
+	local, ok := storage.Arquivos.(*storage.Local)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Rota não encontrada",
+		})
+		return
+	}
+
+	chave := strings.TrimPrefix(c.Param("chave"), "/")
+	if !local.VerificarAssinatura(chave, c.Query("expira"), c.Query("assinatura")) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Link inválido ou expirado",
+		})
+		return
+	}
+
+	arquivo, err := local.Get(c.Request.Context(), chave)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Arquivo não encontrado",
+		})
+		return
+	}
+	defer arquivo.Close()
+
+	// Arquivo local é *os.File: ServeContent trata Range e Content-Type
+	http.ServeContent(c.Writer, c.Request, chave, time.Time{}, arquivo.(io.ReadSeeker))
+}
+
+// ehVideo aceita qualquer MIME video/*
+func ehVideo(tipo *mimetype.MIME) bool {
+	return strings.HasPrefix(tipo.String(), "video/")
+}
+
+// ehImagem aceita fotos JPEG, PNG e WebP
+func ehImagem(tipo *mimetype.MIME) bool {
+	return tipo.Is("image/jpeg") || tipo.Is("image/png") || tipo.Is("image/webp")
+}
+
+// ehImagemOuPDF aceita imagens e PDF (comprovantes)
+func ehImagemOuPDF(tipo *mimetype.MIME) bool {
+	return ehImagem(tipo) || tipo.Is("application/pdf")
+}
+
+// tipoDetectado retorna o MIME detectado ou vazio
+func tipoDetectado(tipo *mimetype.MIME) string {
+	if tipo == nil {
+		return ""
+	}
+	return tipo.String()
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/eventos"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// intervaloPulsoCapturas mantém a conexão aberta em proxies que derrubam conexões ociosas
+const intervaloPulsoCapturas = 30 * time.Second
+
+// AcompanharCapturas abre um WebSocket com os eventos de captura (criada, validada, anulada,
+// deletada). Fiscais recebem apenas as etapas em andamento; organizadores e admins, todas.
+// Navegadores enviam o token no subprotocolo: new WebSocket(url, ["bearer", token]).
+func AcompanharCapturas(c *gin.Context) {
+	tipo := c.GetString("tipo")
+
+	// Filtro opcional de etapas: ?etapa_id=...&etapa_id=...
+	etapas := map[string]bool{}
+	for _, etapaID := range c.QueryArray("etapa_id") {
+		if _, err := uuid.Parse(etapaID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "ID da etapa inválido",
+			})
+			return
+		}
+
+		var etapa models.Etapa
+		if err := database.DB.First(&etapa, "id = ?", etapaID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Etapa não encontrada",
+			})
+			return
+		}
+
+		if !podeAcompanharEtapa(tipo, etapa.Status) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Etapa não está em andamento",
+			})
+			return
+		}
+		etapas[etapaID] = true
+	}
+
+	verMedicoes := podeVerMedicoes(c)
+
+	servidor := websocket.Server{
+		// A autenticação já foi feita pelo token; aceita clientes sem Origin
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			for _, protocolo := range config.Protocol {
+				if strings.EqualFold(protocolo, "bearer") {
+					config.Protocol = []string{protocolo}
+					return nil
+				}
+			}
+			config.Protocol = nil
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			// A conexão sequestrada herda os timeouts do servidor HTTP
+			_ = ws.SetDeadline(time.Time{})
+
+			assinatura := eventos.Capturas.Assinar()
+			defer eventos.Capturas.Cancelar(assinatura)
+
+			// O cliente não envia nada: a leitura só serve para perceber o fechamento
+			fechada := make(chan struct{})
+			go func() {
+				defer close(fechada)
+				var descartar string
+				for websocket.Message.Receive(ws, &descartar) == nil {
+				}
+			}()
+
+			pulso := time.NewTicker(intervaloPulsoCapturas)
+			defer pulso.Stop()
+
+			for {
+				select {
+				case evento, ok := <-assinatura.Eventos:
+					if !ok {
+						return // fila cheia: o cliente deve reconectar e recarregar a lista
+					}
+					if !podeAcompanharEtapa(tipo, evento.EtapaStatus) || (len(etapas) > 0 && !etapas[evento.EtapaID]) {
+						continue
+					}
+					if !verMedicoes {
+						evento.Captura.OcultarMedicoes()
+					}
+					if err := websocket.JSON.Send(ws, evento); err != nil {
+						return
+					}
+				case <-pulso.C:
+					if err := websocket.JSON.Send(ws, gin.H{"tipo": "ping", "momento": time.Now()}); err != nil {
+						return
+					}
+				case <-fechada:
+					return
+				}
+			}
+		},
+	}
+
+	servidor.ServeHTTP(c.Writer, c.Request)
+}
+
+// podeAcompanharEtapa indica se o usuário recebe os eventos de uma etapa com o status informado
+func podeAcompanharEtapa(tipo, status string) bool {
+	if tipo == models.TipoUsuarioAdmin || tipo == models.TipoUsuarioOrganizador {
+		return true
+	}
+	return status == models.StatusEtapaEmAndamento
+}
+
+// publicarEventoCaptura avisa os assinantes do hub sobre uma mudança na captura
+func publicarEventoCaptura(tipo string, captura models.Captura) {
+	var etapa models.Etapa
+	if captura.Inscricao != nil && captura.Inscricao.Etapa != nil {
+		etapa = *captura.Inscricao.Etapa
+	} else {
+		err := database.DB.Joins("JOIN inscricoes ON inscricoes.etapa_id = etapas.id").
+			Where("inscricoes.id = ?", captura.InscricaoID).
+			First(&etapa).Error
+		if err != nil {
+			logrus.Warnf("Evento %s da captura %s não publicado: %v", tipo, captura.ID, err)
+			return
+		}
+	}
+
+	// O evento leva só a captura, sem a inscrição e a etapa carregadas
+	captura.Inscricao = nil
+
+	eventos.Capturas.Publicar(eventos.Evento{
+		Tipo:        tipo,
+		EtapaID:     etapa.ID.String(),
+		EtapaStatus: etapa.Status,
+		Captura:     captura,
+	})
+}
@@ -1,22 +1,34 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/eventos"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// ListarCapturas retorna todas as capturas com filtros
+// errCapturaAlterada indica que outro usuário gravou a captura antes (atualização condicional)
+var errCapturaAlterada = errors.New("captura alterada por outro usuário")
+
+// ListarCapturas retorna todas as capturas com filtros (?format=csv|xlsx exporta planilha)
 func ListarCapturas(c *gin.Context) {
 	etapaID := c.Query("etapa_id")
 	inscricaoID := c.Query("inscricao_id")
 	validado := c.Query("validado")
 	especie := c.Query("especie")
 
+	formato, ok := formatoExportacao(c)
+	if !ok {
+		return
+	}
+
 	var capturas []models.Captura
 	query := database.DB.Preload("Inscricao.Competidor").Preload("Inscricao.Etapa")
 
@@ -37,7 +49,20 @@ func ListarCapturas(c *gin.Context) {
 		query = query.Where("especie = ?", especie)
 	}
 
-	result := query.Order("hora_captura DESC").Find(&capturas)
+	// Fiscais não veem capturas reservadas por outro fiscal na fila
+	if c.GetString("tipo") == models.TipoUsuarioFiscal {
+		query = query.Where("capturas.reservada_por IS NULL OR capturas.reservada_ate < ? OR capturas.reservada_por = ?",
+			time.Now(), c.GetString("user_id"))
+	}
+
+	if formato != "" {
+		exportarCapturas(c, query, formato)
+		return
+	}
+
+	query = query.Order("hora_captura DESC")
+
+	result := query.Find(&capturas)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -46,6 +71,12 @@ func ListarCapturas(c *gin.Context) {
 		return
 	}
 
+	if !podeVerMedicoes(c) {
+		for i := range capturas {
+			capturas[i].OcultarMedicoes()
+		}
+	}
+
 	c.JSON(http.StatusOK, capturas)
 }
 
@@ -64,6 +95,7 @@ func BuscarCaptura(c *gin.Context) {
 	result := database.DB.
 		Preload("Inscricao.Competidor").
 		Preload("Inscricao.Etapa").
+		Preload("Penalidades").
 		First(&captura, "id = ?", id)
 
 	if result.Error != nil {
@@ -73,6 +105,10 @@ func BuscarCaptura(c *gin.Context) {
 		return
 	}
 
+	if !podeVerMedicoes(c) {
+		captura.OcultarMedicoes()
+	}
+
 	c.JSON(http.StatusOK, captura)
 }
 
@@ -104,46 +140,77 @@ func CriarCaptura(c *gin.Context) {
 		return
 	}
 
-	// Validar espécie
-	if !models.ValidarEspecie(captura.Especie) {
+	// Validar espécie no catálogo da edição
+	if inscricao.Etapa == nil || !carregarEspecies(database.DB, inscricao.Etapa.EdicaoID.String()).ValidarEspecie(captura.Especie) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Espécie inválida",
 		})
 		return
 	}
 
-	// Verificar cota de peixes (apenas tucunarés)
-	if captura.Especie != models.EspecieTraira {
-		var count int64
-		database.DB.Model(&models.Captura{}).
-			Where("inscricao_id = ? AND validado = ? AND anulado = ? AND especie != ?",
-				captura.InscricaoID, true, false, models.EspecieTraira).
-			Count(&count)
+	// Medidas exigidas pela etapa: comprimento, peso ou ambos
+	if inscricao.Etapa.MedeComprimento() && captura.TamanhoOriginal <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tamanho é obrigatório nesta etapa",
+		})
+		return
+	}
 
-		if count >= models.CotaMaximaPeixes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Cota máxima de peixes atingida (4 tucunarés)",
-			})
-			return
-		}
+	if inscricao.Etapa.MedePeso() && captura.PesoOriginal <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Peso (g) é obrigatório nesta etapa",
+		})
+		return
 	}
 
 	// Definir valores padrão
+	captura.Unidade = inscricao.Etapa.Unidade()
 	captura.Tamanho = captura.TamanhoOriginal
+	captura.Peso = captura.PesoOriginal
 	captura.Validado = false
 	captura.Anulado = false
+	captura.ContaCota = false
+
+	// Metadados do vídeo só vêm do arquivo enviado (anexarVideo)
+	captura.DuracaoVideo, captura.TamanhoArquivo, captura.DataGravacao = 0, 0, nil
+	captura.LarguraVideo, captura.AlturaVideo = 0, 0
+	captura.VideoCurto, captura.GravadoForaEtapa = false, false
+
+	// Conferência da régua é feita pelo fiscal na validação
+	captura.NumeroReguaVideo = 0
+	captura.ReguaConfere = false
+
+	// Nova captura entra livre na fila dos fiscais
+	captura.ReservadaPor = nil
+	captura.ReservadaAte = nil
+
+	// Medições e status da validação (dupla ou simples) só vêm dos fiscais
+	captura.StatusValidacao = models.StatusValidacaoPendente
+	captura.ValidadoPor, captura.DataValidacao = "", nil
+	captura.TamanhoMedido, captura.PesoMedido = 0, 0
+	captura.Fiscal1ID, captura.Fiscal1Nome, captura.Fiscal1Tamanho, captura.Fiscal1Penalidade, captura.Fiscal1Em = nil, "", 0, 0, nil
+	captura.Fiscal2ID, captura.Fiscal2Nome, captura.Fiscal2Tamanho, captura.Fiscal2Penalidade, captura.Fiscal2Em = nil, "", 0, 0, nil
+
+	// Penalidades são aplicadas pelo fiscal
+	captura.Penalidade, captura.MotivoPenalidade, captura.Penalidades = 0, "", nil
 
 	if captura.HoraCaptura.IsZero() {
 		captura.HoraCaptura = time.Now()
 	}
 
-	// Verificar se está dentro do horário permitido
+	// Verificar se está dentro do horário permitido (largada até retorno, com tolerância)
 	if inscricao.Etapa != nil {
-		// Aqui você pode adicionar validação de horário de retorno
-		// Por exemplo: captura.HoraCaptura deve ser antes das 16h
+		tolerado, err := inscricao.Etapa.VerificarHorario(captura.HoraCaptura)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		captura.ForaDoHorario = tolerado
 	}
 
-	result := database.DB.Create(&captura)
+	result := database.DB.Omit(clause.Associations).Create(&captura)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -152,6 +219,8 @@ func CriarCaptura(c *gin.Context) {
 		return
 	}
 
+	publicarEventoCaptura(eventos.CapturaCriada, captura)
+
 	c.JSON(http.StatusCreated, captura)
 }
 
@@ -167,9 +236,14 @@ func ValidarCaptura(c *gin.Context) {
 	}
 
 	var input struct {
-		Penalidade       float64 `json:"penalidade" binding:"min=0,max=3"`
-		MotivoPenalidade string  `json:"motivo_penalidade"`
-		ValidadoPor      string  `json:"validado_por" binding:"required"`
+		Penalidades        []string `json:"penalidades"`                // códigos do catálogo da edição
+		Penalidade         float64  `json:"penalidade" binding:"min=0"` // valor livre (cm ou g), só para edições sem catálogo
+		MotivoPenalidade   string   `json:"motivo_penalidade"`
+		ValidadoPor        string   `json:"validado_por" binding:"required"`
+		NumeroRegua        int      `json:"numero_regua" binding:"required,min=1"` // número visto no vídeo
+		AceitarDivergencia bool     `json:"aceitar_divergencia"`                   // valida mesmo com régua divergente (fica sinalizada)
+		TamanhoMedido      float64  `json:"tamanho_medido" binding:"min=0"`        // obrigatório na validação dupla
+		PesoMedido         float64  `json:"peso_medido" binding:"min=0"`           // em g; obrigatório na validação dupla por peso
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -179,53 +253,296 @@ func ValidarCaptura(c *gin.Context) {
 		return
 	}
 
-	// Validar penalidade
-	if !models.ValidarPenalidade(input.Penalidade) {
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao.Regua").Preload("Inscricao.Etapa").First(&captura, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return
+	}
+
+	if captura.Validado {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Captura já foi validada",
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if captura.ReservadaPorOutro(userID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Captura reservada por outro fiscal",
+		})
+		return
+	}
+
+	// Conferir a régua do vídeo com a sorteada para o competidor
+	var regua *models.Regua
+	if captura.Inscricao != nil {
+		regua = captura.Inscricao.Regua
+	}
+	if !captura.ConferirRegua(input.NumeroRegua, regua) && !input.AceitarDivergencia {
+		esperado := 0
+		if regua != nil {
+			esperado = regua.Numero
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":           models.ErrReguaInvalida,
+			"numero_video":    input.NumeroRegua,
+			"numero_esperado": esperado,
+		})
+		return
+	}
+
+	var etapa *models.Etapa
+	if captura.Inscricao != nil {
+		etapa = captura.Inscricao.Etapa
+	}
+
+	penalidade, motivo, aplicadas, ok := resolverPenalidades(c, etapa, captura.Unidade, input.Penalidades, input.Penalidade, input.MotivoPenalidade)
+	if !ok {
+		return
+	}
+
+	statusAnterior := captura.StatusValidacao
+
+	if etapa != nil && etapa.DuplaValidacao {
+		// Validação dupla: cada fiscal mede sem ver a medida do outro
+		medida := input.TamanhoMedido
+		if captura.PorPeso() {
+			medida = input.PesoMedido
+		}
+
+		if medida <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Medida do fiscal (tamanho_medido ou peso_medido, conforme a etapa) é obrigatória na validação dupla",
+			})
+			return
+		}
+
+		if captura.StatusValidacao == models.StatusValidacaoDivergente {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Medições divergentes aguardam decisão do organizador",
+			})
+			return
+		}
+
+		if captura.MedidaPor(userID) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "A segunda medição deve ser feita por outro fiscal",
+			})
+			return
+		}
+
+		captura.RegistrarMedicao(userID, input.ValidadoPor, medida, penalidade, motivo)
+		if captura.Fiscal2ID != nil {
+			captura.ConsolidarMedicoes(etapa.ToleranciaMedicao)
+		}
+	} else {
+		captura.TamanhoMedido = input.TamanhoMedido
+		captura.PesoMedido = input.PesoMedido
+		captura.Validar(input.ValidadoPor, penalidade, motivo)
+	}
+
+	// Verificar tamanho mínimo
+	regra := carregarRegras(database.DB, etapa).Para(captura.Especie)
+	if captura.Validado && !captura.AtingeTamanhoMinimo(regra) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Peixe abaixo do tamanho mínimo após penalidade",
+			"tamanho": captura.Tamanho,
+			"minimo":  regra.TamanhoMinimo,
+		})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Gravar só se ninguém validou, anulou ou reservou a captura nesse meio tempo
+		result := tx.Model(&captura).
+			Select("*").Omit(clause.Associations, "id", "created_at").
+			Where("validado = ? AND anulado = ? AND status_validacao = ?", false, false, statusAnterior).
+			Where("reservada_por IS NULL OR reservada_ate < ? OR reservada_por = ?", time.Now(), userID).
+			Updates(&captura)
+
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCapturaAlterada
+		}
+
+		// Medições consolidadas: o detalhamento fica só com os códigos dos dois fiscais
+		if etapa != nil && etapa.DuplaValidacao && captura.Validado {
+			var anteriores []models.CapturaPenalidade
+			if err := tx.Where("captura_id = ?", captura.ID).Find(&anteriores).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("captura_id = ?", captura.ID).Delete(&models.CapturaPenalidade{}).Error; err != nil {
+				return err
+			}
+			return salvarPenalidadesAplicadas(tx, captura.ID.String(), captura.ValidadoPor, models.PenalidadesEmComum(anteriores, aplicadas))
+		}
+
+		return salvarPenalidadesAplicadas(tx, captura.ID.String(), input.ValidadoPor, aplicadas)
+	})
+
+	if errors.Is(err, errCapturaAlterada) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Captura alterada por outro fiscal, recarregue",
+		})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao validar captura",
+		})
+		return
+	}
+
+	if !captura.Validado {
+		mensagem := "Primeira medição registrada, aguardando segundo fiscal"
+		if captura.StatusValidacao == models.StatusValidacaoDivergente {
+			mensagem = "Medições divergentes, captura enviada ao organizador"
+		}
+		if !podeVerMedicoes(c) {
+			captura.OcultarMedicoes()
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": mensagem,
+			"captura": captura,
+		})
+		return
+	}
+
+	atualizarPontuacaoInscricao(captura.InscricaoID)
+	publicarEventoCaptura(eventos.CapturaValidada, captura)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Captura validada com sucesso",
+		"captura": captura,
+	})
+}
+
+// ResolverDivergencia decide a medida final de uma captura com medições divergentes
+func ResolverDivergencia(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Penalidade deve estar entre 0 e 3 cm",
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var input struct {
+		TamanhoMedido    float64  `json:"tamanho_medido" binding:"min=0"`
+		PesoMedido       float64  `json:"peso_medido" binding:"min=0"` // em g, nas etapas por peso
+		Penalidades      []string `json:"penalidades"`                 // códigos do catálogo; substituem os dos fiscais
+		Penalidade       float64  `json:"penalidade" binding:"min=0"`
+		MotivoPenalidade string   `json:"motivo_penalidade"`
+		ValidadoPor      string   `json:"validado_por" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
 		})
 		return
 	}
 
 	var captura models.Captura
-	if err := database.DB.First(&captura, "id = ?", id).Error; err != nil {
+	if err := database.DB.Preload("Inscricao.Etapa").First(&captura, "id = ?", id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Captura não encontrada",
 		})
 		return
 	}
 
-	if captura.Validado {
+	if captura.StatusValidacao != models.StatusValidacaoDivergente || captura.Anulado {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Captura já foi validada",
+			"error": "Captura não está com medições divergentes",
 		})
 		return
 	}
 
-	// Validar captura
-	captura.Validar(input.ValidadoPor, input.Penalidade, input.MotivoPenalidade)
+	var etapa *models.Etapa
+	if captura.Inscricao != nil {
+		etapa = captura.Inscricao.Etapa
+	}
 
-	// Verificar tamanho mínimo
-	if !captura.AtingeTamanhoMinimo() {
+	if (captura.PorPeso() && input.PesoMedido <= 0) || (!captura.PorPeso() && input.TamanhoMedido <= 0) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Informe a medida final (tamanho_medido ou peso_medido, conforme a etapa)",
+		})
+		return
+	}
+
+	penalidade, motivo, aplicadas, ok := resolverPenalidades(c, etapa, captura.Unidade, input.Penalidades, input.Penalidade, input.MotivoPenalidade)
+	if !ok {
+		return
+	}
+	if motivo == "" && len(input.Penalidades) == 0 {
+		motivo = captura.MotivoPenalidade
+	}
+
+	if captura.PorPeso() {
+		captura.PesoMedido = input.PesoMedido
+	} else {
+		captura.TamanhoMedido = input.TamanhoMedido
+	}
+	captura.Validar(input.ValidadoPor, penalidade, motivo)
+
+	regra := carregarRegras(database.DB, etapa).Para(captura.Especie)
+	if !captura.AtingeTamanhoMinimo(regra) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Peixe abaixo do tamanho mínimo após penalidade",
 			"tamanho": captura.Tamanho,
-			"minimo":  models.TamanhoMinimoTucunare,
+			"minimo":  regra.TamanhoMinimo,
 		})
 		return
 	}
 
-	database.DB.Save(&captura)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&captura).
+			Select("*").Omit(clause.Associations, "id", "created_at").
+			Where("status_validacao = ? AND anulado = ?", models.StatusValidacaoDivergente, false).
+			Updates(&captura)
 
-	// Atualizar pontuação da inscrição
-	var inscricao models.Inscricao
-	if err := database.DB.Preload("Capturas").First(&inscricao, "id = ?", captura.InscricaoID).Error; err == nil {
-		inscricao.CalcularPontuacao()
-		database.DB.Save(&inscricao)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCapturaAlterada
+		}
+
+		// A decisão do organizador substitui as penalidades registradas pelos fiscais,
+		// mesmo quando ele decide sem penalidade ou por valor livre
+		if err := tx.Where("captura_id = ?", captura.ID).Delete(&models.CapturaPenalidade{}).Error; err != nil {
+			return err
+		}
+
+		return salvarPenalidadesAplicadas(tx, captura.ID.String(), input.ValidadoPor, aplicadas)
+	})
+
+	if errors.Is(err, errCapturaAlterada) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Captura alterada por outro usuário, recarregue",
+		})
+		return
 	}
 
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao resolver divergência",
+		})
+		return
+	}
+
+	atualizarPontuacaoInscricao(captura.InscricaoID)
+	publicarEventoCaptura(eventos.CapturaValidada, captura)
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Captura validada com sucesso",
+		"message": "Divergência resolvida, captura validada",
 		"captura": captura,
 	})
 }
@@ -260,15 +577,18 @@ func AnularCaptura(c *gin.Context) {
 		return
 	}
 
+	if captura.ReservadaPorOutro(c.GetString("user_id")) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Captura reservada por outro fiscal",
+		})
+		return
+	}
+
 	captura.Anular(input.MotivoAnulacao)
 	database.DB.Save(&captura)
 
-	// Atualizar pontuação da inscrição
-	var inscricao models.Inscricao
-	if err := database.DB.Preload("Capturas").First(&inscricao, "id = ?", captura.InscricaoID).Error; err == nil {
-		inscricao.CalcularPontuacao()
-		database.DB.Save(&inscricao)
-	}
+	atualizarPontuacaoInscricao(captura.InscricaoID)
+	publicarEventoCaptura(eventos.CapturaAnulada, captura)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Captura anulada com sucesso",
@@ -286,23 +606,54 @@ func DeletarCaptura(c *gin.Context) {
 		return
 	}
 
-	result := database.DB.Delete(&models.Captura{}, "id = ?", id)
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Erro ao deletar captura",
+	var captura models.Captura
+	if err := database.DB.First(&captura, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
 		})
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Captura não encontrada",
+	if err := database.DB.Delete(&captura).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar captura",
 		})
 		return
 	}
 
+	publicarEventoCaptura(eventos.CapturaDeletada, captura)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Captura deletada com sucesso",
 	})
 }
+
+// atualizarPontuacaoInscricao recalcula os totais da inscrição a partir das capturas
+func atualizarPontuacaoInscricao(inscricaoID string) {
+	var inscricao models.Inscricao
+	if err := database.DB.Preload("Capturas").Preload("Etapa.Modalidade").First(&inscricao, "id = ?", inscricaoID).Error; err == nil {
+		inscricao.CalcularPontuacao(carregarRegras(database.DB, inscricao.Etapa), models.EscolherEstrategia(inscricao.Etapa))
+		salvarPontuacao(database.DB, &inscricao)
+		notificarPlacar(inscricao.EtapaID)
+	}
+}
+
+// salvarPontuacao grava os totais da inscrição e quais capturas contam na cota
+func salvarPontuacao(db *gorm.DB, inscricao *models.Inscricao) error {
+	if err := db.Omit(clause.Associations).Save(inscricao).Error; err != nil {
+		return err
+	}
+
+	for _, captura := range inscricao.Capturas {
+		if err := db.Model(&models.Captura{}).Where("id = ?", captura.ID).Update("conta_cota", captura.ContaCota).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// podeVerMedicoes indica se o usuário pode ver as medições de cada fiscal antes do fim da validação
+func podeVerMedicoes(c *gin.Context) bool {
+	tipo := c.GetString("tipo")
+	return tipo == models.TipoUsuarioAdmin || tipo == models.TipoUsuarioOrganizador
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/video"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+// arquivoVideo é um vídeo já recebido por completo (upload simples ou tus)
+type arquivoVideo interface {
+	io.Reader
+	io.ReaderAt
+}
+
+// EnviarVideoCaptura recebe o vídeo da captura (multipart, campo "video") e grava no storage
+func EnviarVideoCaptura(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao.Etapa").First(&captura, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return
+	}
+
+	if !podeAlterarCaptura(c, &captura) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Captura pertence a outro competidor",
+		})
+		return
+	}
+
+	if captura.Validado || captura.Anulado {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Vídeo não pode ser substituído após validação ou anulação",
+		})
+		return
+	}
+
+	arquivo, ok := receberArquivo(c, "video", config.AppConfig.Storage.MaxVideoMB, ehVideo)
+	if !ok {
+		return
+	}
+	defer arquivo.Close()
+
+	if err := anexarVideo(c.Request.Context(), &captura, arquivo, arquivo.Tamanho, arquivo.Tipo); err != nil {
+		logrus.Errorf("Erro ao gravar vídeo da captura %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar vídeo",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Vídeo enviado com sucesso",
+		"captura": captura,
+	})
+}
+
+// BaixarVideoCaptura redireciona para o link temporário do vídeo da captura.
+// Competidores só baixam os vídeos das próprias capturas.
+func BaixarVideoCaptura(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao").First(&captura, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return
+	}
+
+	if !podeAlterarCaptura(c, &captura) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Captura pertence a outro competidor",
+		})
+		return
+	}
+
+	redirecionarArquivo(c, captura.VideoArquivo)
+}
+
+// anexarVideo lê os metadados, grava o vídeo no storage e atualiza a captura.
+// A captura deve vir com Inscricao.Etapa carregada para a avaliação do vídeo.
+func anexarVideo(ctx context.Context, captura *models.Captura, arquivo arquivoVideo, tamanho int64, tipo *mimetype.MIME) error {
+	// Metadados só são extraídos de MP4/QuickTime; demais formatos ficam zerados
+	meta, err := video.LerMetadados(arquivo, tamanho)
+	if err != nil {
+		logrus.Warnf("Não foi possível ler os metadados do vídeo da captura %s: %v", captura.ID, err)
+		meta = &video.Metadados{}
+	}
+
+	chave := fmt.Sprintf("capturas/%s/video%s", captura.ID, tipo.Extension())
+	if err := gravarArquivo(ctx, arquivo, tamanho, tipo, chave, captura.VideoArquivo); err != nil {
+		return err
+	}
+
+	captura.VideoArquivo = chave
+	captura.VideoURL = "/api/capturas/" + captura.ID.String() + "/video"
+	captura.TamanhoArquivo = tamanho
+	captura.DuracaoVideo = int(math.Round(meta.Duracao.Seconds()))
+	captura.DataGravacao = meta.DataCriacao
+	captura.LarguraVideo = meta.Largura
+	captura.AlturaVideo = meta.Altura
+	if captura.Inscricao != nil {
+		captura.AvaliarVideo(captura.Inscricao.Etapa)
+	}
+
+	return database.DB.Omit(clause.Associations).Save(captura).Error
+}
+
+// podeAlterarCaptura impede que um competidor altere ou veja o vídeo de capturas de outro;
+// fiscais, organizadores e admins têm acesso a todas
+func podeAlterarCaptura(c *gin.Context, captura *models.Captura) bool {
+	tipo, _ := c.Get("tipo")
+	if tipo != "competidor" {
+		return true
+	}
+
+	userID, _ := c.Get("user_id")
+	return captura.Inscricao != nil && captura.Inscricao.CompetidorID == userID
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListarCategorias retorna as categorias de competidores de uma edição
+func ListarCategorias(c *gin.Context) {
+	edicaoID := c.Query("edicao_id")
+
+	var categorias []models.CategoriaCompetidor
+	query := database.DB.Model(&models.CategoriaCompetidor{})
+
+	if edicaoID != "" {
+		query = query.Where("edicao_id = ?", edicaoID)
+	}
+
+	if c.Query("ativa") != "" {
+		query = query.Where("ativa = ?", c.Query("ativa") == "true")
+	}
+
+	result := query.Order("ordem ASC, nome ASC").Find(&categorias)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar categorias",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, categorias)
+}
+
+// CriarCategoria adiciona uma categoria à edição. Vale para todas as inscrições da edição a partir da
+// próxima geração do ranking de cada etapa, que reenquadra as inscrições nas categorias ativas.
+func CriarCategoria(c *gin.Context) {
+	var categoria models.CategoriaCompetidor
+
+	if err := c.ShouldBindJSON(&categoria); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", categoria.EdicaoID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	if !validarCategoria(c, &categoria) {
+		return
+	}
+
+	if err := database.DB.Create(&categoria).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar categoria: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, categoria)
+}
+
+// AtualizarCategoria altera uma categoria. O código não muda, pois identifica os rankings já gerados.
+func AtualizarCategoria(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var categoria models.CategoriaCompetidor
+	if err := database.DB.First(&categoria, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Categoria não encontrada",
+		})
+		return
+	}
+
+	edicaoID, codigo := categoria.EdicaoID, categoria.Codigo
+	if err := c.ShouldBindJSON(&categoria); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+	categoria.EdicaoID, categoria.Codigo = edicaoID, codigo
+
+	if !validarCategoria(c, &categoria) {
+		return
+	}
+
+	database.DB.Save(&categoria)
+
+	c.JSON(http.StatusOK, categoria)
+}
+
+// DeletarCategoria remove uma categoria (soft delete)
+func DeletarCategoria(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	result := database.DB.Delete(&models.CategoriaCompetidor{}, "id = ?", id)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar categoria",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Categoria não encontrada",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Categoria deletada com sucesso",
+	})
+}
+
+// validarCategoria confere código, sexo e faixa de idade, e se o código é único na edição.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarCategoria(c *gin.Context, categoria *models.CategoriaCompetidor) bool {
+	var ok bool
+	if categoria.Codigo, ok = normalizarCodigo(categoria.Codigo); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Código da categoria deve conter apenas letras minúsculas, números e _",
+		})
+		return false
+	}
+
+	// O código vira categoria do ranking: não pode colidir com as categorias fixas
+	if categoria.Codigo == models.CategoriaGeral || strings.HasPrefix(categoria.Codigo, "maior_") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Código reservado para as categorias do ranking",
+		})
+		return false
+	}
+
+	if categoria.Sexo != "" && categoria.Sexo != models.SexoMasculino && categoria.Sexo != models.SexoFeminino {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Sexo deve ser M, F ou vazio",
+		})
+		return false
+	}
+
+	if categoria.IdadeMaxima > 0 && categoria.IdadeMaxima < categoria.IdadeMinima {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Idade máxima não pode ser menor que a mínima",
+		})
+		return false
+	}
+
+	var count int64
+	database.DB.Model(&models.CategoriaCompetidor{}).
+		Where("edicao_id = ? AND codigo = ? AND id <> ?", categoria.EdicaoID, categoria.Codigo, categoria.ID).
+		Count(&count)
+
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Código de categoria já existe nesta edição",
+		})
+		return false
+	}
+
+	return true
+}
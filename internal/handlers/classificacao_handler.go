@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// classificacaoModalidade é a classificação da temporada de uma modalidade
+type classificacaoModalidade struct {
+	ModalidadeID  string                          `json:"modalidade_id"`
+	Modalidade    string                          `json:"modalidade"`
+	Etapas        []etapaClassificacao            `json:"etapas"`
+	Classificacao []models.ClassificacaoTemporada `json:"classificacao"`
+}
+
+// etapaClassificacao identifica uma etapa que conta para a temporada
+type etapaClassificacao struct {
+	ID     string `json:"id"`
+	Numero int    `json:"numero"`
+	Nome   string `json:"nome"`
+}
+
+// BuscarClassificacaoEdicao retorna a classificação da edição por modalidade, somando os
+// pontos por colocação de cada etapa já classificada, no geral ou em uma categoria
+func BuscarClassificacaoEdicao(c *gin.Context) {
+	id := c.Param("id")
+	modalidadeID := c.Query("modalidade_id")
+	categoria := c.DefaultQuery("categoria", models.CategoriaGeral) // geral ou código da categoria de competidor
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	tabela, err := edicao.TabelaPontosTemporada()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resposta, err := montarClassificacaoEdicao(&edicao, modalidadeID, categoria, tabela)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar etapas",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"edicao_id":           edicao.ID,
+		"categoria":           categoria,
+		"tabela_pontos":       tabela,
+		"descartar_piores":    edicao.DescartarPiores,
+		"desempate_temporada": edicao.CriteriosDesempateTemporada(),
+		"modalidades":         resposta,
+	})
+}
+
+// montarClassificacaoEdicao agrupa por modalidade as etapas já classificadas e soma os pontos
+func montarClassificacaoEdicao(edicao *models.Edicao, modalidadeID, categoria string, tabela []float64) ([]classificacaoModalidade, error) {
+	// Apenas etapas que já têm ranking contam para a temporada
+	var etapas []models.Etapa
+	query := database.DB.Preload("Modalidade").
+		Where("edicao_id = ?", edicao.ID).
+		Where("EXISTS (SELECT 1 FROM rankings WHERE rankings.etapa_id = etapas.id AND rankings.deleted_at IS NULL)")
+
+	if modalidadeID != "" {
+		query = query.Where("modalidade_id = ?", modalidadeID)
+	}
+
+	if err := query.Order("numero ASC, data_largada ASC").Find(&etapas).Error; err != nil {
+		return nil, err
+	}
+
+	// Agrupar etapas por modalidade, mantendo a ordem
+	var ordem []string
+	porModalidade := map[string][]models.Etapa{}
+	for _, etapa := range etapas {
+		chave := etapa.ModalidadeID.String()
+		if _, ok := porModalidade[chave]; !ok {
+			ordem = append(ordem, chave)
+		}
+		porModalidade[chave] = append(porModalidade[chave], etapa)
+	}
+
+	resposta := make([]classificacaoModalidade, 0, len(ordem))
+	for _, chave := range ordem {
+		etapasModalidade := porModalidade[chave]
+
+		item := classificacaoModalidade{
+			ModalidadeID:  chave,
+			Classificacao: classificarTemporada(edicao, etapasModalidade, categoria, tabela),
+		}
+		if etapasModalidade[0].Modalidade != nil {
+			item.Modalidade = etapasModalidade[0].Modalidade.Nome
+		}
+		for _, etapa := range etapasModalidade {
+			item.Etapas = append(item.Etapas, etapaClassificacao{
+				ID:     etapa.ID.String(),
+				Numero: etapa.Numero,
+				Nome:   etapa.Nome,
+			})
+		}
+
+		resposta = append(resposta, item)
+	}
+
+	return resposta, nil
+}
+
+// classificarTemporada monta a classificação a partir do ranking vigente da categoria em cada etapa (em ordem)
+func classificarTemporada(edicao *models.Edicao, etapas []models.Etapa, categoria string, tabela []float64) []models.ClassificacaoTemporada {
+	indice := make(map[string]int, len(etapas))
+	ids := make([]string, len(etapas))
+	for i, etapa := range etapas {
+		ids[i] = etapa.ID.String()
+		indice[ids[i]] = i
+	}
+
+	var rankings []models.Ranking
+	database.DB.Preload("Inscricao.Competidor").
+		Scopes(rankingVigente).
+		Where("etapa_id IN ? AND categoria = ?", ids, categoria).
+		Find(&rankings)
+
+	porCompetidor := map[string]*models.ClassificacaoTemporada{}
+	var competidores []string
+
+	for _, ranking := range rankings {
+		if ranking.Inscricao == nil {
+			continue
+		}
+
+		competidorID := ranking.Inscricao.CompetidorID
+		linha, ok := porCompetidor[competidorID]
+		if !ok {
+			linha = &models.ClassificacaoTemporada{
+				CompetidorID: competidorID,
+				Resultados:   make([]models.ResultadoEtapa, len(etapas)),
+			}
+			if ranking.Inscricao.Competidor != nil {
+				linha.Nome = ranking.Inscricao.Competidor.Nome
+			}
+			for i, etapa := range etapas {
+				linha.Resultados[i] = models.ResultadoEtapa{EtapaID: ids[i], EtapaNumero: etapa.Numero}
+			}
+			porCompetidor[competidorID] = linha
+			competidores = append(competidores, competidorID)
+		}
+
+		resultado := &linha.Resultados[indice[ranking.EtapaID]]
+		resultado.Posicao = ranking.Posicao
+		resultado.PontuacaoEtapa = ranking.PontuacaoTotal
+		resultado.Pontos = models.PontosPorPosicao(tabela, ranking.Posicao)
+	}
+
+	classificacao := make([]models.ClassificacaoTemporada, 0, len(competidores))
+	for _, competidorID := range competidores {
+		linha := porCompetidor[competidorID]
+		linha.Totalizar(edicao.DescartarPiores)
+		classificacao = append(classificacao, *linha)
+	}
+
+	models.ClassificarTemporada(classificacao, edicao.CriteriosDesempateTemporada())
+
+	return classificacao
+}
@@ -1,18 +1,25 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// ListarCompetidores retorna todos os competidores
+// ListarCompetidores retorna todos os competidores (?format=csv|xlsx exporta planilha)
 func ListarCompetidores(c *gin.Context) {
 	var competidores []models.Competidor
 
+	formato, ok := formatoExportacao(c)
+	if !ok {
+		return
+	}
+
 	query := database.DB.Select("id", "nome", "email", "telefone", "cidade", "estado", "ativo", "banido")
 
 	// Filtro por status
@@ -24,7 +31,14 @@ func ListarCompetidores(c *gin.Context) {
 		query = query.Where("banido = ?", banido == "true")
 	}
 
-	result := query.Order("nome ASC").Find(&competidores)
+	if formato != "" {
+		exportarCompetidores(c, query, formato)
+		return
+	}
+
+	query = query.Order("nome ASC")
+
+	result := query.Find(&competidores)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -220,3 +234,87 @@ func DesbanirCompetidor(c *gin.Context) {
 		"message": "Banimento removido com sucesso",
 	})
 }
+
+// EnviarFotoCompetidor recebe a foto do competidor (multipart, campo "foto")
+func EnviarFotoCompetidor(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	// Competidor só pode alterar a própria foto
+	tipo, _ := c.Get("tipo")
+	userID, _ := c.Get("user_id")
+	if tipo == "competidor" && userID != id {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return
+	}
+
+	var competidor models.Competidor
+	if err := database.DB.First(&competidor, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Competidor não encontrado",
+		})
+		return
+	}
+
+	arquivo, ok := receberArquivo(c, "foto", config.AppConfig.Storage.MaxArquivoMB, ehImagem)
+	if !ok {
+		return
+	}
+	defer arquivo.Close()
+
+	chave := fmt.Sprintf("competidores/%s/foto%s", competidor.ID, arquivo.Tipo.Extension())
+	if err := gravarArquivo(c.Request.Context(), arquivo, arquivo.Tamanho, arquivo.Tipo, chave, competidor.FotoArquivo); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar foto",
+		})
+		return
+	}
+
+	competidor.FotoArquivo = chave
+	competidor.FotoURL = "/api/competidores/" + competidor.ID.String() + "/foto"
+
+	if err := database.DB.Model(&competidor).Updates(map[string]interface{}{
+		"foto_arquivo": competidor.FotoArquivo,
+		"foto_url":     competidor.FotoURL,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao atualizar competidor: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Foto enviada com sucesso",
+		"foto_url": competidor.FotoURL,
+	})
+}
+
+// BaixarFotoCompetidor redireciona para o link temporário da foto do competidor
+func BaixarFotoCompetidor(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var competidor models.Competidor
+	if err := database.DB.Select("id", "foto_arquivo").First(&competidor, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Competidor não encontrado",
+		})
+		return
+	}
+
+	redirecionarArquivo(c, competidor.FotoArquivo)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
@@ -84,6 +86,10 @@ func CriarEdicao(c *gin.Context) {
 		return
 	}
 
+	if !validarClassificacaoEdicao(c, &edicao) {
+		return
+	}
+
 	// Se for ativa, desativar outras edições
 	if edicao.Ativa {
 		database.DB.Model(&models.Edicao{}).
@@ -130,6 +136,10 @@ func AtualizarEdicao(c *gin.Context) {
 		return
 	}
 
+	if !validarClassificacaoEdicao(c, &edicao) {
+		return
+	}
+
 	// Se for ativa, desativar outras edições
 	if edicao.Ativa {
 		database.DB.Model(&models.Edicao{}).
@@ -174,3 +184,103 @@ func DeletarEdicao(c *gin.Context) {
 		"message": "Edição deletada com sucesso",
 	})
 }
+
+// EnviarImagemEdicao recebe a imagem de divulgação da edição (multipart, campo "imagem")
+func EnviarImagemEdicao(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validar UUID
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	arquivo, ok := receberArquivo(c, "imagem", config.AppConfig.Storage.MaxArquivoMB, ehImagem)
+	if !ok {
+		return
+	}
+	defer arquivo.Close()
+
+	chave := fmt.Sprintf("edicoes/%s/imagem%s", edicao.ID, arquivo.Tipo.Extension())
+	if err := gravarArquivo(c.Request.Context(), arquivo, arquivo.Tamanho, arquivo.Tipo, chave, edicao.ImagemArquivo); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar imagem",
+		})
+		return
+	}
+
+	edicao.ImagemArquivo = chave
+	edicao.ImagemURL = "/api/edicoes/" + edicao.ID.String() + "/imagem"
+
+	if err := database.DB.Model(&edicao).Updates(map[string]interface{}{
+		"imagem_arquivo": edicao.ImagemArquivo,
+		"imagem_url":     edicao.ImagemURL,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao atualizar edição: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, edicao)
+}
+
+// BaixarImagemEdicao redireciona para o link temporário da imagem da edição
+func BaixarImagemEdicao(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validar UUID
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	redirecionarArquivo(c, edicao.ImagemArquivo)
+}
+
+// validarClassificacaoEdicao confere a tabela de pontos, os descartes e os desempates da temporada.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarClassificacaoEdicao(c *gin.Context, edicao *models.Edicao) bool {
+	if _, err := edicao.TabelaPontosTemporada(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	if edicao.DescartarPiores < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Descartes não podem ser negativos",
+		})
+		return false
+	}
+
+	if err := models.ValidarDesempateTemporada(edicao.DesempateTemporada); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListarEspecies retorna o catálogo de espécies de uma edição.
+// Edição sem espécies cadastradas usa o catálogo padrão do regulamento.
+func ListarEspecies(c *gin.Context) {
+	edicaoID := c.Query("edicao_id")
+
+	if edicaoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Informe edicao_id",
+		})
+		return
+	}
+
+	if _, err := uuid.Parse(edicaoID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	especies := carregarEspecies(database.DB, edicaoID)
+	if c.Query("ativa") == "true" {
+		especies = especies.Ativas()
+	}
+
+	if especies == nil {
+		especies = models.CatalogoEspecies{}
+	}
+
+	c.JSON(http.StatusOK, especies)
+}
+
+// CriarEspecie adiciona uma espécie ao catálogo da edição. Na primeira espécie própria,
+// o catálogo padrão é gravado junto para que tucunarés e traíra continuem valendo.
+func CriarEspecie(c *gin.Context) {
+	var especie models.Especie
+
+	if err := c.ShouldBindJSON(&especie); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", especie.EdicaoID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	if !validarEspecieCatalogo(c, &especie) {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var cadastradas int64
+		if err := tx.Model(&models.Especie{}).Where("edicao_id = ?", especie.EdicaoID).Count(&cadastradas).Error; err != nil {
+			return err
+		}
+
+		if cadastradas == 0 {
+			padrao := models.EspeciesPadrao()
+			for i := range padrao {
+				padrao[i].EdicaoID = especie.EdicaoID
+			}
+			if err := tx.Create(&padrao).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&especie).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar espécie: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, especie)
+}
+
+// AtualizarEspecie altera uma espécie do catálogo. O código não muda, pois
+// identifica as capturas e as regras já cadastradas.
+func AtualizarEspecie(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var especie models.Especie
+	if err := database.DB.First(&especie, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Espécie não encontrada",
+		})
+		return
+	}
+
+	edicaoID, codigo := especie.EdicaoID, especie.Codigo
+	if err := c.ShouldBindJSON(&especie); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+	especie.EdicaoID, especie.Codigo = edicaoID, codigo
+
+	database.DB.Save(&especie)
+
+	c.JSON(http.StatusOK, especie)
+}
+
+// DeletarEspecie remove uma espécie do catálogo (soft delete).
+// Espécie com capturas na edição deve ser desativada, não removida.
+func DeletarEspecie(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var especie models.Especie
+	if err := database.DB.First(&especie, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Espécie não encontrada",
+		})
+		return
+	}
+
+	var capturas int64
+	database.DB.Model(&models.Captura{}).
+		Joins("JOIN inscricoes ON capturas.inscricao_id = inscricoes.id").
+		Joins("JOIN etapas ON inscricoes.etapa_id = etapas.id").
+		Where("etapas.edicao_id = ? AND capturas.especie = ?", especie.EdicaoID, especie.Codigo).
+		Count(&capturas)
+
+	if capturas > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Espécie possui capturas nesta edição: desative em vez de remover",
+		})
+		return
+	}
+
+	database.DB.Delete(&especie)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Espécie deletada com sucesso",
+	})
+}
+
+// validarEspecieCatalogo normaliza o código e confere se é único na edição (incluindo o catálogo padrão).
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarEspecieCatalogo(c *gin.Context, especie *models.Especie) bool {
+	var ok bool
+	if especie.Codigo, ok = normalizarCodigo(especie.Codigo); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Código da espécie deve conter apenas letras minúsculas, números e _",
+		})
+		return false
+	}
+
+	if len(especie.Codigo) > models.TamanhoMaximoCodigoEspecie {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Código da espécie deve ter no máximo %d caracteres", models.TamanhoMaximoCodigoEspecie),
+		})
+		return false
+	}
+
+	for _, codigo := range carregarEspecies(database.DB, especie.EdicaoID.String()).Codigos() {
+		if codigo == especie.Codigo {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Código de espécie já existe nesta edição",
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizarCodigo põe o código em minúsculas e confere se tem apenas letras, números e _
+func normalizarCodigo(codigo string) (string, bool) {
+	codigo = strings.ToLower(strings.TrimSpace(codigo))
+	if codigo == "" {
+		return "", false
+	}
+
+	for _, r := range codigo {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return codigo, false
+		}
+	}
+	return codigo, true
+}
+
+// carregarEspecies retorna o catálogo da edição, ou o padrão se nada foi cadastrado
+func carregarEspecies(db *gorm.DB, edicaoID string) models.CatalogoEspecies {
+	var especies models.CatalogoEspecies
+	db.Where("edicao_id = ?", edicaoID).Order("ordem ASC, nome_comum ASC").Find(&especies)
+
+	if len(especies) == 0 {
+		return models.EspeciesPadrao()
+	}
+
+	return especies
+}
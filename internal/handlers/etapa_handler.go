@@ -76,6 +76,10 @@ func CriarEtapa(c *gin.Context) {
 		return
 	}
 
+	if !validarEtapa(c, &etapa) {
+		return
+	}
+
 	// Validar se a edição existe
 	var edicao models.Edicao
 	if err := database.DB.First(&edicao, "id = ?", etapa.EdicaoID).Error; err != nil {
@@ -132,6 +136,10 @@ func AtualizarEtapa(c *gin.Context) {
 		return
 	}
 
+	if !validarEtapa(c, &etapa) {
+		return
+	}
+
 	database.DB.Save(&etapa)
 
 	c.JSON(http.StatusOK, etapa)
@@ -168,3 +176,54 @@ func DeletarEtapa(c *gin.Context) {
 		"message": "Etapa deletada com sucesso",
 	})
 }
+
+// validarEtapa confere janela de pesca, tolerâncias, desempate, medição e estratégia de pontuação.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarEtapa(c *gin.Context, etapa *models.Etapa) bool {
+	// Validar fuso e horários da janela de pesca
+	if _, _, err := etapa.JanelaPesca(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	if etapa.ToleranciaMinutos < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tolerância não pode ser negativa",
+		})
+		return false
+	}
+
+	if etapa.ToleranciaMedicao < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tolerância de medição não pode ser negativa",
+		})
+		return false
+	}
+
+	if err := models.ValidarDesempate(etapa.Desempate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	if etapa.Medicao != "" && !models.ValidarModoMedicao(etapa.Medicao) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Medição inválida: use comprimento, peso ou ambos",
+		})
+		return false
+	}
+
+	if etapa.EstrategiaPontuacao != "" {
+		if _, err := models.NovaEstrategiaPontuacao(etapa.EstrategiaPontuacao, etapa.PontosPorPeixe); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return false
+		}
+	}
+
+	return true
+}
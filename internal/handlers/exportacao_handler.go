@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/planilha"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loteExportacao é quantos registros são lidos do banco por vez nas exportações
+const loteExportacao = 500
+
+// formatoExportacao lê ?format (vazio = JSON). Em caso de formato inválido a resposta já é enviada e retorna false.
+func formatoExportacao(c *gin.Context) (string, bool) {
+	formato := c.Query("format")
+	if formato == "" || planilha.ValidarFormato(formato) {
+		return formato, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":    "Formato inválido",
+		"formatos": []string{planilha.FormatoCSV, planilha.FormatoXLSX},
+	})
+	return "", false
+}
+
+// colunaExportacao é uma coluna da ordem de uma exportação e como ler o seu valor no registro.
+// A última coluna deve ser o id, para a ordem ser total.
+type colunaExportacao[T any] struct {
+	nome  string
+	desc  bool
+	valor func(registro *T) any
+}
+
+// exportarEmLotes percorre a consulta em lotes, sem carregar todos os registros na memória.
+// Cada lote começa depois do último registro do anterior (paginação pela chave, sem OFFSET), então o custo
+// não cresce ao longo da exportação e registros gravados no meio dela não fazem linhas repetirem ou sumirem.
+func exportarEmLotes[T any](query *gorm.DB, ordem []colunaExportacao[T], gravar func(lote []T) error) error {
+	for _, coluna := range ordem {
+		if coluna.desc {
+			query = query.Order(coluna.nome + " DESC")
+		} else {
+			query = query.Order(coluna.nome + " ASC")
+		}
+	}
+	query = query.Session(&gorm.Session{})
+
+	var ultimo *T
+	for {
+		consulta := query
+		if ultimo != nil {
+			consulta = consulta.Where(depoisDe(ordem, ultimo))
+		}
+
+		var lote []T
+		if err := consulta.Limit(loteExportacao).Find(&lote).Error; err != nil {
+			return err
+		}
+		if err := gravar(lote); err != nil {
+			return err
+		}
+		if len(lote) < loteExportacao {
+			return nil
+		}
+		ultimo = &lote[len(lote)-1]
+	}
+}
+
+// depoisDe monta a condição dos registros que vêm depois do informado na ordem:
+// (a > ?) OR (a = ? AND b > ?) OR ..., com < nas colunas decrescentes
+func depoisDe[T any](ordem []colunaExportacao[T], registro *T) clause.Expression {
+	var alternativas, iguais []clause.Expression
+	for _, coluna := range ordem {
+		valor := coluna.valor(registro)
+		operador := " > ?"
+		if coluna.desc {
+			operador = " < ?"
+		}
+
+		condicoes := append(append([]clause.Expression{}, iguais...), clause.Expr{SQL: coluna.nome + operador, Vars: []any{valor}})
+		alternativas = append(alternativas, clause.And(condicoes...))
+		iguais = append(iguais, clause.Expr{SQL: coluna.nome + " = ?", Vars: []any{valor}})
+	}
+	return clause.Or(alternativas...)
+}
+
+// exportarPlanilha envia a planilha como download, gravando as linhas à medida que os lotes chegam
+func exportarPlanilha[T any](c *gin.Context, query *gorm.DB, ordem []colunaExportacao[T], formato, nome string, colunas []string, linha func(registro *T) []any) {
+	local, err := time.LoadLocation(models.FusoPadrao)
+	if err != nil {
+		local = time.Local
+	}
+
+	nomeArquivo := fmt.Sprintf("%s-%s.%s", nome, time.Now().In(local).Format("20060102-1504"), formato)
+	c.Header("Content-Type", planilha.ContentType(formato))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nomeArquivo))
+
+	escritor, err := planilha.Novo(formato, c.Writer, nome, colunas, local)
+	if err == nil {
+		err = exportarEmLotes(query, ordem, func(lote []T) error {
+			for i := range lote {
+				if err := escritor.Linha(linha(&lote[i])...); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err == nil {
+		err = escritor.Fechar()
+	}
+
+	if err != nil {
+		// Se nada foi enviado ainda dá para responder com erro; senão o arquivo fica truncado
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro ao exportar " + nome,
+			})
+			return
+		}
+		logrus.Errorf("Erro ao exportar %s: %v", nome, err)
+	}
+}
+
+// exportarRankings gera a planilha de rankings
+func exportarRankings(c *gin.Context, query *gorm.DB, formato string) {
+	colunas := []string{
+		"Etapa", "Categoria", "Versão", "Posição", "Empatado", "Competidor", "Cidade", "UF",
+		"Peixes", "Maior peixe", "Unidade", "Pontuação", "Premiação", "Valor do prêmio", "Troféu",
+		"Prêmio pago", "Data do pagamento", "Forma de pagamento",
+	}
+
+	ordem := []colunaExportacao[models.Ranking]{
+		{nome: "rankings.etapa_id", valor: func(ranking *models.Ranking) any { return ranking.EtapaID }},
+		{nome: "rankings.categoria", valor: func(ranking *models.Ranking) any { return ranking.Categoria }},
+		{nome: "rankings.posicao", valor: func(ranking *models.Ranking) any { return ranking.Posicao }},
+		{nome: "rankings.id", valor: func(ranking *models.Ranking) any { return ranking.ID }},
+	}
+
+	exportarPlanilha(c, query, ordem, formato, "rankings", colunas, func(ranking *models.Ranking) []any {
+		etapa := ""
+		if ranking.Etapa != nil {
+			etapa = fmt.Sprintf("%dª Etapa - %s", ranking.Etapa.Numero, ranking.Etapa.Nome)
+		}
+
+		var versao any = ranking.Versao
+		if ranking.Versao == 0 {
+			versao = "Provisória"
+		}
+
+		competidor := &models.Competidor{}
+		if ranking.Inscricao != nil && ranking.Inscricao.Competidor != nil {
+			competidor = ranking.Inscricao.Competidor
+		}
+
+		return []any{
+			etapa, ranking.Categoria, versao, ranking.Posicao, ranking.Empatado,
+			competidor.Nome, competidor.Cidade, competidor.Estado,
+			ranking.QuantidadePeixes, ranking.MaiorPeixe, ranking.Unidade, ranking.PontuacaoTotal,
+			ranking.Premiacao, ranking.ValorPremiacao, ranking.Trofeu,
+			ranking.PremioPago, ranking.DataPagamento, ranking.FormaPagamento,
+		}
+	})
+}
+
+// exportarInscricoes gera a planilha de inscrições
+func exportarInscricoes(c *gin.Context, query *gorm.DB, formato string) {
+	colunas := []string{
+		"Etapa", "Competidor", "E-mail", "Telefone", "Cidade", "UF", "Régua", "Data da inscrição",
+		"Status do pagamento", "Valor pago", "Data do pagamento", "Régua devolvida",
+		"Eliminado", "Motivo da eliminação", "Peixes", "Pontuação",
+	}
+
+	ordem := []colunaExportacao[models.Inscricao]{
+		{nome: "inscricoes.data_inscricao", desc: true, valor: func(inscricao *models.Inscricao) any { return inscricao.DataInscricao }},
+		{nome: "inscricoes.id", valor: func(inscricao *models.Inscricao) any { return inscricao.ID }},
+	}
+
+	exportarPlanilha(c, query, ordem, formato, "inscricoes", colunas, func(inscricao *models.Inscricao) []any {
+		etapa := ""
+		if inscricao.Etapa != nil {
+			etapa = fmt.Sprintf("%dª Etapa - %s", inscricao.Etapa.Numero, inscricao.Etapa.Nome)
+		}
+
+		competidor := &models.Competidor{}
+		if inscricao.Competidor != nil {
+			competidor = inscricao.Competidor
+		}
+
+		var regua any
+		if inscricao.Regua != nil {
+			regua = inscricao.Regua.Numero
+		}
+
+		return []any{
+			etapa, competidor.Nome, competidor.Email, competidor.Telefone, competidor.Cidade, competidor.Estado,
+			regua, inscricao.DataInscricao, inscricao.StatusPagamento, inscricao.ValorPago, inscricao.DataPagamento,
+			inscricao.ReguaDevolvida, inscricao.Eliminado, inscricao.MotivoEliminacao,
+			inscricao.QuantidadePeixes, inscricao.PontuacaoTotal,
+		}
+	})
+}
+
+// exportarCapturas gera a planilha de capturas, com o nome das espécies do catálogo de cada edição
+func exportarCapturas(c *gin.Context, query *gorm.DB, formato string) {
+	colunas := []string{
+		"Data/hora", "Etapa", "Competidor", "Espécie", "Tamanho informado", "Peso informado (g)",
+		"Tamanho medido", "Peso medido (g)", "Penalidade", "Motivo da penalidade", "Unidade",
+		"Status da validação", "Validado", "Validado por", "Fora do horário", "Conta na cota",
+		"Anulado", "Motivo da anulação", "Vídeo",
+	}
+
+	ocultar := !podeVerMedicoes(c)
+	catalogos := map[string]models.CatalogoEspecies{}
+
+	ordem := []colunaExportacao[models.Captura]{
+		{nome: "capturas.hora_captura", desc: true, valor: func(captura *models.Captura) any { return captura.HoraCaptura }},
+		{nome: "capturas.id", valor: func(captura *models.Captura) any { return captura.ID }},
+	}
+
+	exportarPlanilha(c, query, ordem, formato, "capturas", colunas, func(captura *models.Captura) []any {
+		if ocultar {
+			captura.OcultarMedicoes()
+		}
+
+		etapa, competidor, especie := "", "", captura.Especie
+		if captura.Inscricao != nil {
+			if captura.Inscricao.Competidor != nil {
+				competidor = captura.Inscricao.Competidor.Nome
+			}
+			if captura.Inscricao.Etapa != nil {
+				etapa = fmt.Sprintf("%dª Etapa - %s", captura.Inscricao.Etapa.Numero, captura.Inscricao.Etapa.Nome)
+
+				edicaoID := captura.Inscricao.Etapa.EdicaoID.String()
+				if _, ok := catalogos[edicaoID]; !ok {
+					catalogos[edicaoID] = carregarEspecies(database.DB, edicaoID)
+				}
+				especie = catalogos[edicaoID].GetNomeEspecie(captura.Especie)
+			}
+		}
+
+		return []any{
+			captura.HoraCaptura, etapa, competidor, especie, captura.TamanhoOriginal, captura.PesoOriginal,
+			captura.TamanhoMedido, captura.PesoMedido, captura.Penalidade, captura.MotivoPenalidade, captura.Unidade,
+			captura.StatusValidacao, captura.Validado, captura.ValidadoPor, captura.ForaDoHorario, captura.ContaCota,
+			captura.Anulado, captura.MotivoAnulacao, captura.VideoURL,
+		}
+	})
+}
+
+// exportarCompetidores gera a planilha de competidores, com os mesmos campos da listagem
+func exportarCompetidores(c *gin.Context, query *gorm.DB, formato string) {
+	colunas := []string{"Nome", "E-mail", "Telefone", "Cidade", "UF", "Ativo", "Banido"}
+
+	ordem := []colunaExportacao[models.Competidor]{
+		{nome: "competidores.nome", valor: func(competidor *models.Competidor) any { return competidor.Nome }},
+		{nome: "competidores.id", valor: func(competidor *models.Competidor) any { return competidor.ID }},
+	}
+
+	exportarPlanilha(c, query, ordem, formato, "competidores", colunas, func(competidor *models.Competidor) []any {
+		return []any{
+			competidor.Nome, competidor.Email, competidor.Telefone, competidor.Cidade, competidor.Estado,
+			competidor.Ativo, competidor.Banido,
+		}
+	})
+}
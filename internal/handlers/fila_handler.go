@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProximaCapturaFila reserva ao fiscal a próxima captura pendente (mais antiga primeiro).
+// Se o fiscal já tem uma reserva vigente, ela é renovada e devolvida.
+func ProximaCapturaFila(c *gin.Context) {
+	etapaID := c.Query("etapa_id")
+	userID := c.GetString("user_id")
+
+	if etapaID != "" {
+		if _, err := uuid.Parse(etapaID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "ID da etapa inválido",
+			})
+			return
+		}
+	}
+
+	var captura models.Captura
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Captura{}).
+			Select("capturas.*").
+			Where("capturas.validado = ? AND capturas.anulado = ?", false, false).
+			Where("capturas.reservada_por IS NULL OR capturas.reservada_ate < ? OR capturas.reservada_por = ?", time.Now(), userID).
+			// Validação dupla: divergentes vão ao organizador e o segundo fiscal deve ser outro
+			Where("capturas.status_validacao <> ?", models.StatusValidacaoDivergente).
+			Where("capturas.fiscal1_id IS NULL OR capturas.fiscal1_id <> ?", userID)
+
+		if etapaID != "" {
+			query = query.Joins("JOIN inscricoes ON capturas.inscricao_id = inscricoes.id").
+				Where("inscricoes.etapa_id = ?", etapaID)
+		}
+
+		// SKIP LOCKED: dois fiscais pedindo ao mesmo tempo recebem capturas diferentes
+		err := query.
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "capturas"}, Options: "SKIP LOCKED"}).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "CASE WHEN capturas.reservada_por = ? THEN 0 ELSE 1 END, capturas.hora_captura ASC",
+				Vars: []interface{}{userID},
+			}}).
+			Take(&captura).Error
+		if err != nil {
+			return err
+		}
+
+		captura.Reservar(userID, models.DuracaoReservaMinutos*time.Minute)
+		return tx.Model(&captura).Updates(map[string]interface{}{
+			"reservada_por": captura.ReservadaPor,
+			"reservada_ate": captura.ReservadaAte,
+		}).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar próxima captura",
+		})
+		return
+	}
+
+	database.DB.Preload("Inscricao.Competidor").Preload("Inscricao.Etapa").First(&captura, "id = ?", captura.ID)
+
+	captura.OcultarMedicoes()
+
+	c.JSON(http.StatusOK, captura)
+}
+
+// LiberarReservaCaptura devolve a captura à fila antes do fim da reserva.
+// Fiscais só liberam as próprias reservas; organizador e admin liberam qualquer uma.
+func LiberarReservaCaptura(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	query := database.DB.Model(&models.Captura{}).Where("id = ? AND reservada_por IS NOT NULL", id)
+	if c.GetString("tipo") == models.TipoUsuarioFiscal {
+		query = query.Where("reservada_por = ?", c.GetString("user_id"))
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"reservada_por": nil,
+		"reservada_ate": nil,
+	})
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao liberar captura",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Nenhuma reserva sua para esta captura",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Captura devolvida à fila",
+	})
+}
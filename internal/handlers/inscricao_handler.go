@@ -1,21 +1,29 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-// ListarInscricoes retorna todas as inscrições com filtros
+// ListarInscricoes retorna todas as inscrições com filtros (?format=csv|xlsx exporta planilha)
 func ListarInscricoes(c *gin.Context) {
 	etapaID := c.Query("etapa_id")
 	competidorID := c.Query("competidor_id")
 	status := c.Query("status_pagamento")
 
+	formato, ok := formatoExportacao(c)
+	if !ok {
+		return
+	}
+
 	var inscricoes []models.Inscricao
 	query := database.DB.Preload("Etapa").Preload("Competidor").Preload("Regua")
 
@@ -31,7 +39,14 @@ func ListarInscricoes(c *gin.Context) {
 		query = query.Where("status_pagamento = ?", status)
 	}
 
-	result := query.Order("data_inscricao DESC").Find(&inscricoes)
+	if formato != "" {
+		exportarInscricoes(c, query, formato)
+		return
+	}
+
+	query = query.Order("data_inscricao DESC")
+
+	result := query.Find(&inscricoes)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -60,6 +75,7 @@ func BuscarInscricao(c *gin.Context) {
 		Preload("Competidor").
 		Preload("Regua").
 		Preload("Capturas").
+		Preload("Categorias").
 		First(&inscricao, "id = ?", id)
 
 	if result.Error != nil {
@@ -72,6 +88,65 @@ func BuscarInscricao(c *gin.Context) {
 	c.JSON(http.StatusOK, inscricao)
 }
 
+// BuscarCotaInscricao mostra quais capturas contam na cota e quais foram deslocadas por peixes maiores
+func BuscarCotaInscricao(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var inscricao models.Inscricao
+	result := database.DB.
+		Preload("Capturas", func(db *gorm.DB) *gorm.DB {
+			// Pela medida pontuada de cada captura: peso nas etapas por peso, senão tamanho
+			return db.Order("CASE WHEN unidade = '" + models.UnidadePeso + "' THEN peso ELSE tamanho END DESC, hora_captura ASC")
+		}).
+		Preload("Etapa.Modalidade").
+		First(&inscricao, "id = ?", id)
+
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Inscrição não encontrada",
+		})
+		return
+	}
+
+	if !podeVerInscricao(c, &inscricao) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return
+	}
+
+	contam := []models.Captura{}
+	pendentes := []models.Captura{}
+	for _, captura := range inscricao.Capturas {
+		if captura.ContaCota {
+			contam = append(contam, captura)
+		} else if !captura.Validado && !captura.Anulado {
+			pendentes = append(pendentes, captura)
+		}
+	}
+
+	deslocadas := inscricao.CapturasDeslocadas(carregarRegras(database.DB, inscricao.Etapa))
+	if deslocadas == nil {
+		deslocadas = []models.Captura{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pontuacao_total":   inscricao.PontuacaoTotal,
+		"quantidade_peixes": inscricao.QuantidadePeixes,
+		"cota_maxima":       models.CotaEstrategia(models.EscolherEstrategia(inscricao.Etapa)),
+		"contam":            contam,
+		"deslocadas":        deslocadas,
+		"pendentes":         pendentes,
+	})
+}
+
 // CriarInscricao cria uma nova inscrição
 func CriarInscricao(c *gin.Context) {
 	var inscricao models.Inscricao
@@ -130,6 +205,11 @@ func CriarInscricao(c *gin.Context) {
 		return
 	}
 
+	// Enquadrar nas categorias da edição pela idade na data da etapa e pelo sexo
+	var categorias []models.CategoriaCompetidor
+	database.DB.Where("edicao_id = ? AND ativa = ?", etapa.EdicaoID, true).Find(&categorias)
+	inscricao.Categorias = models.EnquadrarCategorias(categorias, &competidor, etapa.DataLargada)
+
 	// Definir valores padrão
 	inscricao.DataInscricao = time.Now()
 	inscricao.ValorPago = etapa.ValorInscricao
@@ -269,3 +349,102 @@ func DevolverRegua(c *gin.Context) {
 		"message": "Régua devolvida com sucesso",
 	})
 }
+
+// EnviarComprovante recebe o comprovante de pagamento (multipart, campo "comprovante")
+func EnviarComprovante(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var inscricao models.Inscricao
+	if err := database.DB.First(&inscricao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Inscrição não encontrada",
+		})
+		return
+	}
+
+	if !podeVerInscricao(c, &inscricao) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return
+	}
+
+	arquivo, ok := receberArquivo(c, "comprovante", config.AppConfig.Storage.MaxArquivoMB, ehImagemOuPDF)
+	if !ok {
+		return
+	}
+	defer arquivo.Close()
+
+	chave := fmt.Sprintf("inscricoes/%s/comprovante%s", inscricao.ID, arquivo.Tipo.Extension())
+	if err := gravarArquivo(c.Request.Context(), arquivo, arquivo.Tamanho, arquivo.Tipo, chave, inscricao.ComprovanteArquivo); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar comprovante",
+		})
+		return
+	}
+
+	inscricao.ComprovanteArquivo = chave
+	inscricao.ComprovantePgto = "/api/inscricoes/" + inscricao.ID.String() + "/comprovante"
+
+	if err := database.DB.Model(&inscricao).Updates(map[string]interface{}{
+		"comprovante_arquivo": inscricao.ComprovanteArquivo,
+		"comprovante_pgto":    inscricao.ComprovantePgto,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao atualizar inscrição: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Comprovante enviado com sucesso",
+		"comprovante_pgto": inscricao.ComprovantePgto,
+	})
+}
+
+// BaixarComprovante redireciona para o link temporário do comprovante
+func BaixarComprovante(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var inscricao models.Inscricao
+	if err := database.DB.First(&inscricao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Inscrição não encontrada",
+		})
+		return
+	}
+
+	if !podeVerInscricao(c, &inscricao) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return
+	}
+
+	redirecionarArquivo(c, inscricao.ComprovanteArquivo)
+}
+
+// podeVerInscricao limita o competidor às próprias inscrições
+func podeVerInscricao(c *gin.Context, inscricao *models.Inscricao) bool {
+	tipo, _ := c.Get("tipo")
+	if tipo != "competidor" {
+		return true
+	}
+
+	userID, _ := c.Get("user_id")
+	return inscricao.CompetidorID == userID
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // ListarModalidades retorna todas as modalidades ativas
@@ -25,3 +26,48 @@ func ListarModalidades(c *gin.Context) {
 
 	c.JSON(http.StatusOK, modalidades)
 }
+
+// AtualizarPontuacaoModalidade define a estratégia de pontuação padrão das etapas da modalidade
+func AtualizarPontuacaoModalidade(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var input struct {
+		EstrategiaPontuacao string  `json:"estrategia_pontuacao" binding:"required"`
+		PontosPorPeixe      float64 `json:"pontos_por_peixe"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := models.NovaEstrategiaPontuacao(input.EstrategiaPontuacao, input.PontosPorPeixe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var modalidade models.Modalidade
+	if err := database.DB.First(&modalidade, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Modalidade não encontrada",
+		})
+		return
+	}
+
+	modalidade.EstrategiaPontuacao = input.EstrategiaPontuacao
+	modalidade.PontosPorPeixe = input.PontosPorPeixe
+	database.DB.Save(&modalidade)
+
+	c.JSON(http.StatusOK, modalidade)
+}
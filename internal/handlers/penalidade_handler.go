@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListarPenalidades retorna o catálogo de penalidades de uma edição
+func ListarPenalidades(c *gin.Context) {
+	edicaoID := c.Query("edicao_id")
+
+	var penalidades []models.Penalidade
+	query := database.DB.Model(&models.Penalidade{})
+
+	if edicaoID != "" {
+		query = query.Where("edicao_id = ?", edicaoID)
+	}
+
+	if c.Query("ativa") != "" {
+		query = query.Where("ativa = ?", c.Query("ativa") == "true")
+	}
+
+	result := query.Order("codigo ASC").Find(&penalidades)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar penalidades",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, penalidades)
+}
+
+// CriarPenalidade adiciona uma penalidade ao catálogo da edição
+func CriarPenalidade(c *gin.Context) {
+	var penalidade models.Penalidade
+
+	if err := c.ShouldBindJSON(&penalidade); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", penalidade.EdicaoID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	if !validarPenalidadeCatalogo(c, &penalidade) {
+		return
+	}
+
+	if err := database.DB.Create(&penalidade).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar penalidade: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, penalidade)
+}
+
+// AtualizarPenalidade altera uma penalidade do catálogo.
+// Capturas já validadas mantêm o valor e a descrição da época.
+func AtualizarPenalidade(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var penalidade models.Penalidade
+	if err := database.DB.First(&penalidade, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Penalidade não encontrada",
+		})
+		return
+	}
+
+	edicaoID := penalidade.EdicaoID
+	if err := c.ShouldBindJSON(&penalidade); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+	penalidade.EdicaoID = edicaoID
+
+	if !validarPenalidadeCatalogo(c, &penalidade) {
+		return
+	}
+
+	database.DB.Save(&penalidade)
+
+	c.JSON(http.StatusOK, penalidade)
+}
+
+// DeletarPenalidade remove uma penalidade do catálogo (soft delete)
+func DeletarPenalidade(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	result := database.DB.Delete(&models.Penalidade{}, "id = ?", id)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar penalidade",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Penalidade não encontrada",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Penalidade deletada com sucesso",
+	})
+}
+
+// EstatisticasPenalidades conta as infrações mais frequentes em capturas validadas
+func EstatisticasPenalidades(c *gin.Context) {
+	edicaoID := c.Query("edicao_id")
+	etapaID := c.Query("etapa_id")
+
+	type estatistica struct {
+		Codigo      string `json:"codigo"`
+		Descricao   string `json:"descricao"`
+		Ocorrencias int64  `json:"ocorrencias"`
+	}
+
+	// Na validação dupla cada fiscal registra suas penalidades: conta uma vez por captura
+	query := database.DB.Table("captura_penalidades AS cp").
+		Select("cp.codigo, MAX(cp.descricao) AS descricao, COUNT(DISTINCT cp.captura_id) AS ocorrencias").
+		Joins("JOIN capturas ON capturas.id = cp.captura_id").
+		Joins("JOIN inscricoes ON inscricoes.id = capturas.inscricao_id").
+		Joins("JOIN etapas ON etapas.id = inscricoes.etapa_id").
+		Where("cp.deleted_at IS NULL AND capturas.deleted_at IS NULL").
+		Where("capturas.validado = ? AND capturas.anulado = ?", true, false)
+
+	if edicaoID != "" {
+		query = query.Where("etapas.edicao_id = ?", edicaoID)
+	}
+
+	if etapaID != "" {
+		query = query.Where("etapas.id = ?", etapaID)
+	}
+
+	var estatisticas []estatistica
+	result := query.Group("cp.codigo").Order("ocorrencias DESC, cp.codigo ASC").Scan(&estatisticas)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao calcular estatísticas de penalidades",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, estatisticas)
+}
+
+// validarPenalidadeCatalogo confere limites e código único na edição.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarPenalidadeCatalogo(c *gin.Context, penalidade *models.Penalidade) bool {
+	unidade := penalidade.UnidadeOuPadrao()
+	if unidade != models.UnidadeComprimento && unidade != models.UnidadePeso {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unidade da penalidade deve ser cm ou g",
+		})
+		return false
+	}
+
+	if penalidade.Valor < models.PenalidadeMinimaEm(unidade) || !models.ValidarPenalidade(penalidade.Valor, unidade) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Valor da penalidade deve estar entre %.1f e %.1f %s", models.PenalidadeMinimaEm(unidade), models.PenalidadeMaximaEm(unidade), unidade),
+		})
+		return false
+	}
+
+	var count int64
+	database.DB.Model(&models.Penalidade{}).
+		Where("edicao_id = ? AND codigo = ? AND id <> ?", penalidade.EdicaoID, penalidade.Codigo, penalidade.ID).
+		Count(&count)
+
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Código de penalidade já existe nesta edição",
+		})
+		return false
+	}
+
+	return true
+}
+
+// resolverPenalidades converte os códigos informados pelo fiscal em valor, motivo e detalhamento,
+// na unidade da captura. Sem códigos vale o valor livre, desde que a edição não tenha catálogo
+// cadastrado. Em caso de erro a resposta já é enviada e ok retorna false.
+func resolverPenalidades(c *gin.Context, etapa *models.Etapa, unidade string, codigos []string, valor float64, observacao string) (float64, string, []models.CapturaPenalidade, bool) {
+	if unidade == "" {
+		unidade = models.UnidadeComprimento
+	}
+
+	var catalogo []models.Penalidade
+	if etapa != nil {
+		database.DB.Where("edicao_id = ?", etapa.EdicaoID).Find(&catalogo)
+	}
+
+	if len(codigos) == 0 {
+		if valor > 0 && len(catalogo) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Esta edição usa o catálogo de penalidades: informe os códigos",
+			})
+			return 0, "", nil, false
+		}
+		if !models.ValidarPenalidade(valor, unidade) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Penalidade deve estar entre 0 e %.1f %s", models.PenalidadeMaximaEm(unidade), unidade),
+			})
+			return 0, "", nil, false
+		}
+		return valor, observacao, nil, true
+	}
+
+	aplicadas, total, limitado, err := models.AplicarPenalidades(catalogo, codigos, unidade)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return 0, "", nil, false
+	}
+
+	motivo := models.DescreverPenalidades(aplicadas)
+	if limitado {
+		// Registrar no motivo que a soma do detalhamento foi reduzida ao máximo
+		motivo += fmt.Sprintf(" (total limitado a %.1f %s)", total, unidade)
+	}
+	if observacao != "" {
+		motivo += " - " + observacao
+	}
+
+	return total, motivo, aplicadas, true
+}
+
+// salvarPenalidadesAplicadas grava o detalhamento das penalidades de uma captura
+func salvarPenalidadesAplicadas(tx *gorm.DB, capturaID, aplicadaPor string, aplicadas []models.CapturaPenalidade) error {
+	if len(aplicadas) == 0 {
+		return nil
+	}
+
+	for i := range aplicadas {
+		aplicadas[i].CapturaID = capturaID
+		aplicadas[i].AplicadaPor = aplicadaPor
+	}
+
+	return tx.Create(&aplicadas).Error
+}
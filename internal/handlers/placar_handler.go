@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/placar"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// intervaloPulsoPlacar mantém a conexão aberta em proxies que derrubam streams ociosos
+const intervaloPulsoPlacar = 25 * time.Second
+
+// placarMu serializa os recálculos, para um placar antigo não sobrescrever um mais novo
+var placarMu sync.Mutex
+
+// AcompanharRankingEtapa envia o ranking provisório da etapa por Server-Sent Events.
+// O primeiro evento traz o placar completo; depois chegam só as linhas que mudaram a cada
+// captura validada ou anulada. Reconectando com Last-Event-ID o cliente recebe o que perdeu.
+func AcompanharRankingEtapa(c *gin.Context) {
+	etapaID := c.Param("id")
+
+	if _, err := uuid.Parse(etapaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var etapa models.Etapa
+	if err := database.DB.First(&etapa, "id = ?", etapaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Etapa não encontrada",
+		})
+		return
+	}
+
+	// Primeiro assinante: o quadro nasce com o placar calculado agora
+	placarMu.Lock()
+	var linhas []placar.Linha
+	if !placar.Acompanhada(etapaID) {
+		var err error
+		if linhas, err = montarPlacar(etapaID); err != nil {
+			placarMu.Unlock()
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro ao montar ranking provisório",
+			})
+			return
+		}
+	}
+	iniciais, eventos, cancelar := placar.Assinar(etapaID, c.GetHeader("Last-Event-ID"), linhas)
+	placarMu.Unlock()
+	defer cancelar()
+
+	// O stream fica aberto além do WriteTimeout do servidor
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	for _, evento := range iniciais {
+		enviarEventoPlacar(c, evento)
+	}
+	c.Writer.Flush()
+
+	pulso := time.NewTicker(intervaloPulsoPlacar)
+	defer pulso.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case evento, ok := <-eventos:
+			if !ok {
+				return false // assinante derrubado: o cliente reconecta com Last-Event-ID
+			}
+			enviarEventoPlacar(c, evento)
+			return true
+		case <-pulso.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// enviarEventoPlacar escreve um evento do placar no stream
+func enviarEventoPlacar(c *gin.Context, evento placar.Evento) {
+	c.Render(-1, sse.Event{
+		Id:    evento.ID,
+		Event: evento.Tipo,
+		Data:  evento.Dados,
+	})
+}
+
+// atualizarPlacar recalcula o ranking provisório da etapa, sem gravar nada, e publica as mudanças
+func atualizarPlacar(etapaID string) error {
+	placarMu.Lock()
+	defer placarMu.Unlock()
+
+	linhas, err := montarPlacar(etapaID)
+	if err != nil {
+		return err
+	}
+
+	placar.Publicar(etapaID, linhas)
+	return nil
+}
+
+// montarPlacar calcula as linhas do ranking provisório da etapa
+func montarPlacar(etapaID string) ([]placar.Linha, error) {
+	var etapa models.Etapa
+	if err := database.DB.Preload("Modalidade").First(&etapa, "id = ?", etapaID).Error; err != nil {
+		return nil, err
+	}
+
+	inscricoes, err := buscarInscricoesClassificaveis(database.DB, etapaID)
+	if err != nil {
+		return nil, err
+	}
+
+	classificados, _ := classificarInscricoes(database.DB, &etapa, inscricoes)
+
+	nomes := make(map[string]string, len(inscricoes))
+	for _, inscricao := range inscricoes {
+		if inscricao.Competidor != nil {
+			nomes[inscricao.ID.String()] = inscricao.Competidor.Nome
+		}
+	}
+
+	linhas := make([]placar.Linha, 0, len(classificados))
+	for _, cl := range classificados {
+		linhas = append(linhas, placar.Linha{
+			InscricaoID:      cl.InscricaoID,
+			Competidor:       nomes[cl.InscricaoID],
+			Posicao:          cl.Posicao,
+			Empatado:         cl.Empatado,
+			PontuacaoTotal:   cl.PontuacaoTotal,
+			MaiorPeixe:       cl.MaiorPeixe,
+			QuantidadePeixes: cl.QuantidadePeixes,
+			Unidade:          etapa.Unidade(),
+		})
+	}
+
+	return linhas, nil
+}
+
+// notificarPlacar recalcula em segundo plano o placar da etapa, se alguém o acompanha
+func notificarPlacar(etapaID string) {
+	if !placar.Acompanhada(etapaID) {
+		return
+	}
+
+	go func() {
+		if err := atualizarPlacar(etapaID); err != nil {
+			logrus.Errorf("Erro ao atualizar o placar da etapa %s: %v", etapaID, err)
+		}
+	}()
+}
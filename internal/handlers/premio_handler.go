@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListarPremios retorna a tabela de premiação de uma etapa
+func ListarPremios(c *gin.Context) {
+	etapaID := c.Query("etapa_id")
+	categoria := c.Query("categoria")
+
+	if _, err := uuid.Parse(etapaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Informe um etapa_id válido",
+		})
+		return
+	}
+
+	var premios []models.Premio
+	query := database.DB.Where("etapa_id = ?", etapaID)
+
+	if categoria != "" {
+		query = query.Where("categoria = ?", categoria)
+	}
+
+	result := query.Order("categoria ASC, posicao_inicial ASC").Find(&premios)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar prêmios",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, premios)
+}
+
+// CriarPremio adiciona uma faixa à tabela de premiação. Vale a partir da próxima geração do ranking.
+func CriarPremio(c *gin.Context) {
+	var premio models.Premio
+
+	if err := c.ShouldBindJSON(&premio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	if !validarPremio(c, &premio) {
+		return
+	}
+
+	if err := database.DB.Create(&premio).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar prêmio: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, premio)
+}
+
+// AtualizarPremio altera uma faixa da tabela de premiação
+func AtualizarPremio(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var premio models.Premio
+	if err := database.DB.First(&premio, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Prêmio não encontrado",
+		})
+		return
+	}
+
+	etapaID := premio.EtapaID
+	if err := c.ShouldBindJSON(&premio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+	premio.EtapaID = etapaID
+
+	if !validarPremio(c, &premio) {
+		return
+	}
+
+	database.DB.Save(&premio)
+
+	c.JSON(http.StatusOK, premio)
+}
+
+// DeletarPremio remove uma faixa da tabela de premiação (soft delete)
+func DeletarPremio(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	result := database.DB.Delete(&models.Premio{}, "id = ?", id)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar prêmio",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Prêmio não encontrado",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Prêmio deletado com sucesso",
+	})
+}
+
+// RegistrarPagamentoPremio marca o prêmio de uma linha do ranking oficial vigente como pago ou não pago
+func RegistrarPagamentoPremio(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var input struct {
+		Pago           *bool      `json:"pago" binding:"required"`
+		DataPagamento  *time.Time `json:"data_pagamento"` // padrão: agora
+		FormaPagamento string     `json:"forma_pagamento"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	if *input.Pago && !models.ValidarFormaPagamento(input.FormaPagamento) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Forma de pagamento inválida",
+			"formas": models.GetFormasPagamento(),
+		})
+		return
+	}
+
+	var ranking models.Ranking
+	if err := database.DB.First(&ranking, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ranking não encontrado",
+		})
+		return
+	}
+
+	if !ranking.Oficial() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Publique o ranking antes de registrar pagamentos",
+		})
+		return
+	}
+
+	if !ranking.Premiado() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Esta colocação não tem prêmio",
+		})
+		return
+	}
+
+	// Pagamentos só na versão vigente; as anteriores ficam como estavam
+	var posteriores int64
+	database.DB.Model(&models.PublicacaoRanking{}).
+		Where("etapa_id = ? AND versao > ?", ranking.EtapaID, ranking.Versao).
+		Count(&posteriores)
+
+	if posteriores > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Existe versão mais nova do ranking oficial; registre o pagamento nela",
+		})
+		return
+	}
+
+	ranking.RegistrarPagamento(*input.Pago, input.DataPagamento, input.FormaPagamento, c.GetString("nome"))
+
+	// Apenas os campos de pagamento mudam: o resultado publicado continua congelado
+	err := database.DB.Model(&ranking).
+		Select("premio_pago", "data_pagamento", "forma_pagamento", "pago_por").
+		Updates(&ranking).Error
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao registrar pagamento",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ranking)
+}
+
+// validarPremio confere a faixa, a categoria e se não cruza com outra faixa da mesma categoria.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarPremio(c *gin.Context, premio *models.Premio) bool {
+	if err := premio.Normalizar(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	var etapa models.Etapa
+	if err := database.DB.First(&etapa, "id = ?", premio.EtapaID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Etapa não encontrada",
+		})
+		return false
+	}
+
+	premio.Categoria = strings.TrimSpace(premio.Categoria)
+	if !categoriaRankingExiste(etapa.EdicaoID.String(), premio.Categoria) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Categoria inválida: use geral, maior_<espécie> ou o código de uma categoria da edição",
+		})
+		return false
+	}
+
+	var outros []models.Premio
+	database.DB.Where("etapa_id = ? AND categoria = ? AND id <> ?", premio.EtapaID, premio.Categoria, premio.ID).
+		Find(&outros)
+
+	for i := range outros {
+		if premio.Sobrepoe(&outros[i]) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Faixa de posições cruza com o prêmio \"" + outros[i].Descricao + "\"",
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+// categoriaRankingExiste confere se a categoria é uma das que o ranking da edição gera
+func categoriaRankingExiste(edicaoID, categoria string) bool {
+	if categoria == models.CategoriaGeral {
+		return true
+	}
+
+	for _, especie := range carregarEspecies(database.DB, edicaoID) {
+		if especie.CategoriaMaiorPeixe() == categoria {
+			return true
+		}
+	}
+
+	var count int64
+	database.DB.Model(&models.CategoriaCompetidor{}).
+		Where("edicao_id = ? AND codigo = ?", edicaoID, categoria).
+		Count(&count)
+
+	return count > 0
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// errRankingVazio indica que a etapa não tem ranking provisório para publicar
+var errRankingVazio = errors.New("gere o ranking da etapa antes de publicar")
+
+// PublicarRanking congela o ranking provisório da etapa como nova versão oficial.
+// A primeira publicação não exige motivo; as correções seguintes, sim.
+func PublicarRanking(c *gin.Context) {
+	etapaID := c.Param("id")
+
+	if _, err := uuid.Parse(etapaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var input struct {
+		Motivo string `json:"motivo"`
+	}
+
+	// Corpo opcional na primeira publicação
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Dados inválidos: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	var etapa models.Etapa
+	if err := database.DB.First(&etapa, "id = ?", etapaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Etapa não encontrada",
+		})
+		return
+	}
+
+	var publicacao *models.PublicacaoRanking
+	var erroValidacao error
+
+	// Sob o mesmo lock da geração: ninguém refaz o provisório durante a cópia
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := travarRankingEtapa(tx, etapaID); err != nil {
+			return err
+		}
+
+		var provisorio []models.Ranking
+		if err := tx.Where("etapa_id = ? AND versao = ?", etapaID, 0).Order("categoria ASC, posicao ASC").Find(&provisorio).Error; err != nil {
+			return err
+		}
+
+		if len(provisorio) == 0 {
+			erroValidacao = errRankingVazio
+			return nil
+		}
+
+		var ultimaVersao int
+		if err := tx.Model(&models.PublicacaoRanking{}).Where("etapa_id = ?", etapaID).
+			Select("COALESCE(MAX(versao), 0)").Scan(&ultimaVersao).Error; err != nil {
+			return err
+		}
+
+		publicacao, erroValidacao = models.NovaPublicacao(etapaID, ultimaVersao, input.Motivo, c.GetString("nome"), c.GetString("user_id"))
+		if erroValidacao != nil {
+			return nil
+		}
+
+		publicacao.ID = uuid.New()
+		linhas := publicacao.Congelar(provisorio)
+
+		// Prêmios já pagos na versão anterior continuam pagos na correção
+		if ultimaVersao > 0 {
+			var anteriores []models.Ranking
+			if err := tx.Where("etapa_id = ? AND versao = ? AND premio_pago = ?", etapaID, ultimaVersao, true).Find(&anteriores).Error; err != nil {
+				return err
+			}
+
+			pagos := make(map[string]*models.Ranking, len(anteriores))
+			for i := range anteriores {
+				pagos[anteriores[i].Categoria+"|"+anteriores[i].InscricaoID] = &anteriores[i]
+			}
+			for i := range linhas {
+				if anterior, ok := pagos[linhas[i].Categoria+"|"+linhas[i].InscricaoID]; ok {
+					linhas[i].HerdarPagamento(anterior)
+				}
+			}
+		}
+
+		if err := tx.Create(publicacao).Error; err != nil {
+			return err
+		}
+		return tx.Create(&linhas).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao publicar ranking: " + err.Error(),
+		})
+		return
+	}
+
+	if erroValidacao != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": erroValidacao.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Ranking oficial publicado",
+		"publicacao": publicacao,
+	})
+}
+
+// ListarPublicacoesRanking retorna o histórico de versões oficiais do ranking da etapa
+func ListarPublicacoesRanking(c *gin.Context) {
+	etapaID := c.Param("id")
+
+	if _, err := uuid.Parse(etapaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var publicacoes []models.PublicacaoRanking
+	result := database.DB.Where("etapa_id = ?", etapaID).Order("versao DESC").Find(&publicacoes)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar publicações",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, publicacoes)
+}
+
+// rankingVigente filtra as linhas da última versão oficial de cada etapa,
+// ou do ranking provisório quando a etapa ainda não foi publicada
+func rankingVigente(db *gorm.DB) *gorm.DB {
+	return db.Where("rankings.versao = (SELECT COALESCE(MAX(p.versao), 0) FROM publicacoes_ranking p " +
+		"WHERE p.etapa_id = rankings.etapa_id AND p.deleted_at IS NULL)")
+}
+
+// filtrarVersaoRanking aplica o parâmetro ?versao: vazio = vigente, "provisorio" ou 0 = provisório,
+// N = versão oficial N. Retorna false se o valor é inválido.
+func filtrarVersaoRanking(query *gorm.DB, versao string) (*gorm.DB, bool) {
+	switch versao {
+	case "":
+		return query.Scopes(rankingVigente), true
+	case "provisorio":
+		return query.Where("rankings.versao = ?", 0), true
+	}
+
+	numero, err := strconv.Atoi(versao)
+	if err != nil || numero < 0 {
+		return query, false
+	}
+	return query.Where("rankings.versao = ?", numero), true
+}
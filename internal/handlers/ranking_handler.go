@@ -1,18 +1,21 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
 	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // BuscarRankingEtapa retorna o ranking de uma etapa
 func BuscarRankingEtapa(c *gin.Context) {
 	etapaID := c.Param("id")
-	categoria := c.Query("categoria") // geral, maior_azul, maior_amarelo, maior_traira
+	categoria := c.Query("categoria") // geral, maior_<espécie> ou código da categoria de competidor
+	versao := c.Query("versao")       // vazio = vigente, provisorio ou número da versão oficial
 	
 	if _, err := uuid.Parse(etapaID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -31,9 +34,15 @@ func BuscarRankingEtapa(c *gin.Context) {
 	}
 	
 	var rankings []models.Ranking
-	query := database.DB.Where("etapa_id = ?", etapaID).
-		Preload("Inscricao.Competidor").
-		Preload("Etapa")
+	query, ok := filtrarVersaoRanking(database.DB.Where("etapa_id = ?", etapaID), versao)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Versão inválida",
+		})
+		return
+	}
+	
+	query = query.Preload("Inscricao.Competidor").Preload("Etapa")
 	
 	if categoria != "" {
 		query = query.Where("categoria = ?", categoria)
@@ -51,7 +60,8 @@ func BuscarRankingEtapa(c *gin.Context) {
 	c.JSON(http.StatusOK, rankings)
 }
 
-// GerarRanking gera/atualiza o ranking de uma etapa
+// GerarRanking gera/atualiza o ranking provisório de uma etapa. As versões oficiais já
+// publicadas não mudam: para corrigir, publique uma nova versão.
 func GerarRanking(c *gin.Context) {
 	etapaID := c.Param("id")
 	
@@ -71,138 +81,294 @@ func GerarRanking(c *gin.Context) {
 		return
 	}
 	
-	// Limpar ranking anterior
-	database.DB.Where("etapa_id = ?", etapaID).Delete(&models.Ranking{})
+	total, err := gerarRankingEtapa(etapaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao gerar ranking: " + err.Error(),
+		})
+		return
+	}
+	
+	if total == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Nenhuma inscrição válida encontrada",
+		})
+		return
+	}
+	
+	var versaoOficial int
+	database.DB.Model(&models.PublicacaoRanking{}).Where("etapa_id = ?", etapaID).
+		Select("COALESCE(MAX(versao), 0)").Scan(&versaoOficial)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Ranking gerado com sucesso",
+		"total_competidores": total,
+		"versao_oficial":     versaoOficial, // 0 = ainda não publicado
+	})
+}
+
+// gerarRankingEtapa recria o ranking da etapa e retorna o número de competidores classificados.
+// Tudo roda em uma transação sob o lock da etapa: quem lê continua vendo o ranking anterior
+// até o commit, e duas gerações simultâneas da mesma etapa são feitas uma após a outra.
+func gerarRankingEtapa(etapaID string) (int, error) {
+	total := 0
+	
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := travarRankingEtapa(tx, etapaID); err != nil {
+			return err
+		}
+	
+		var etapa models.Etapa
+		if err := tx.Preload("Modalidade").First(&etapa, "id = ?", etapaID).Error; err != nil {
+			return err
+		}
+	
+		// Limpar ranking provisório anterior (as versões oficiais ficam)
+		if err := tx.Where("etapa_id = ? AND versao = ?", etapaID, 0).Delete(&models.Ranking{}).Error; err != nil {
+			return err
+		}
+	
+		// Buscar todas as inscrições válidas com capturas
+		inscricoes, err := buscarInscricoesClassificaveis(tx, etapaID)
+		if err != nil {
+			return err
+		}
+	
+		if len(inscricoes) == 0 {
+			return nil
+		}
+	
+		// Calcular pontuações, ordenar com os critérios de desempate e criar ranking geral
+		classificados, detalhes := classificarInscricoes(tx, &etapa, inscricoes)
+		for i := range inscricoes {
+			if err := salvarPontuacao(tx, &inscricoes[i]); err != nil {
+				return err
+			}
+		}
+	
+		// Tabela de premiação da etapa, aplicada a todas as categorias
+		var premios models.TabelaPremios
+		if err := tx.Where("etapa_id = ?", etapaID).Find(&premios).Error; err != nil {
+			return err
+		}
+	
+		if err := criarRankingsClassificacao(tx, &etapa, models.CategoriaGeral, "", classificados, detalhes, premios); err != nil {
+			return err
+		}
+	
+		// Rankings por categoria de competidor (infantil, feminino, master...)
+		var categorias []models.CategoriaCompetidor
+		if err := tx.Where("edicao_id = ? AND ativa = ?", etapa.EdicaoID, true).Order("ordem ASC").Find(&categorias).Error; err != nil {
+			return err
+		}
+	
+		// Enquadrar de novo a cada geração: o cadastro do competidor e as categorias podem ter
+		// mudado desde a inscrição
+		porInscricao := make(map[string]*models.Inscricao, len(inscricoes))
+		for i := range inscricoes {
+			if err := reenquadrarCategorias(tx, &etapa, categorias, &inscricoes[i]); err != nil {
+				return err
+			}
+			porInscricao[inscricoes[i].ID.String()] = &inscricoes[i]
+		}
+	
+		for _, categoria := range categorias {
+			var daCategoria []models.Classificado
+			for _, cl := range classificados {
+				if porInscricao[cl.InscricaoID].TemCategoria(categoria.Codigo) {
+					daCategoria = append(daCategoria, cl)
+				}
+			}
+	
+			if len(daCategoria) == 0 {
+				continue
+			}
+	
+			models.Classificar(daCategoria, etapa.CriteriosDesempate())
+			if err := criarRankingsClassificacao(tx, &etapa, categoria.Codigo, categoria.Nome, daCategoria, detalhes, premios); err != nil {
+				return err
+			}
+		}
+	
+		// Gerar rankings de maiores peixes por espécie ativa na edição
+		for _, especie := range carregarEspecies(tx, etapa.EdicaoID.String()).Ativas() {
+			if err := gerarRankingMaiorPeixe(tx, &etapa, especie, premios); err != nil {
+				return err
+			}
+		}
+	
+		total = len(classificados)
+		return nil
+	})
 	
-	// Buscar todas as inscrições válidas com capturas
+	if err != nil {
+		return 0, err
+	}
+	
+	return total, nil
+}
+
+// buscarInscricoesClassificaveis retorna as inscrições pagas e não eliminadas, com as capturas validadas
+func buscarInscricoesClassificaveis(db *gorm.DB, etapaID string) ([]models.Inscricao, error) {
 	var inscricoes []models.Inscricao
-	database.DB.Where("etapa_id = ? AND status_pagamento = ? AND eliminado = ?", 
+	err := db.Where("etapa_id = ? AND status_pagamento = ? AND eliminado = ?",
 		etapaID, models.StatusPagamentoPago, false).
 		Preload("Competidor").
 		Preload("Capturas", "validado = ? AND anulado = ?", true, false).
-		Find(&inscricoes)
+		Find(&inscricoes).Error
 	
-	if len(inscricoes) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Nenhuma inscrição válida encontrada",
-		})
-		return
+	return inscricoes, err
+}
+
+// reenquadrarCategorias enquadra a inscrição nas categorias ativas da edição pela idade na data da
+// etapa e pelo sexo atuais do competidor, gravando o resultado
+func reenquadrarCategorias(tx *gorm.DB, etapa *models.Etapa, categorias []models.CategoriaCompetidor, inscricao *models.Inscricao) error {
+	var enquadradas []models.CategoriaCompetidor
+	if inscricao.Competidor != nil {
+		enquadradas = models.EnquadrarCategorias(categorias, inscricao.Competidor, etapa.DataLargada)
 	}
 	
-	// Calcular pontuações e criar ranking geral
-	type RankingTemp struct {
-		Inscricao        *models.Inscricao
-		PontuacaoTotal   float64
-		MaiorPeixe       float64
-		QuantidadePeixes int
+	if err := tx.Model(inscricao).Association("Categorias").Replace(enquadradas); err != nil {
+		return err
 	}
+	inscricao.Categorias = enquadradas
+	return nil
+}
+	
+// classificarInscricoes calcula a pontuação de cada inscrição e ordena pelos critérios de desempate da etapa
+func classificarInscricoes(db *gorm.DB, etapa *models.Etapa, inscricoes []models.Inscricao) ([]models.Classificado, map[string]models.DetalhePontuacao) {
+	// Regras de tamanho e cota das espécies e estratégia de pontuação desta etapa
+	regras := carregarRegras(db, etapa)
+	estrategia := models.EscolherEstrategia(etapa)
 	
-	var rankingTemp []RankingTemp
+	classificados := make([]models.Classificado, 0, len(inscricoes))
+	detalhes := make(map[string]models.DetalhePontuacao, len(inscricoes))
 	
 	for i := range inscricoes {
 		inscricao := &inscricoes[i]
-		pontuacao := inscricao.CalcularPontuacao()
-		database.DB.Save(inscricao)
-		
-		// Encontrar maior peixe
-		maiorPeixe := 0.0
-		for _, captura := range inscricao.Capturas {
-			if captura.Tamanho > maiorPeixe {
-				maiorPeixe = captura.Tamanho
-			}
-		}
-		
-		rankingTemp = append(rankingTemp, RankingTemp{
-			Inscricao:        inscricao,
-			PontuacaoTotal:   pontuacao,
-			MaiorPeixe:       maiorPeixe,
+		inscricao.Etapa = etapa
+		detalhe := inscricao.CalcularPontuacao(regras, estrategia)
+	
+		// Maior peixe só entre os que contaram na cota, com a medida limitada pela regra
+		classificados = append(classificados, models.Classificado{
+			InscricaoID:      inscricao.ID.String(),
+			PontuacaoTotal:   detalhe.Total,
+			MaiorPeixe:       detalhe.MaiorComputado(),
 			QuantidadePeixes: inscricao.QuantidadePeixes,
+			UltimaCaptura:    inscricao.UltimaCapturaContada(),
 		})
+		detalhes[inscricao.ID.String()] = detalhe
 	}
 	
-	// Ordenar por pontuação (maior primeiro)
-	for i := 0; i < len(rankingTemp); i++ {
-		for j := i + 1; j < len(rankingTemp); j++ {
-			if rankingTemp[j].PontuacaoTotal > rankingTemp[i].PontuacaoTotal {
-				rankingTemp[i], rankingTemp[j] = rankingTemp[j], rankingTemp[i]
-			}
-		}
+	models.Classificar(classificados, etapa.CriteriosDesempate())
+	
+	return classificados, detalhes
+}
+
+// travarRankingEtapa pega o advisory lock da etapa, liberado no fim da transação
+func travarRankingEtapa(tx *gorm.DB, etapaID string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "ranking:"+etapaID).Error
+}
+
+// criarRankingsClassificacao grava as linhas de uma classificação já ordenada, na categoria informada
+func criarRankingsClassificacao(tx *gorm.DB, etapa *models.Etapa, categoria, nomeCategoria string, classificados []models.Classificado, detalhes map[string]models.DetalhePontuacao, premios models.TabelaPremios) error {
+	rankings := make([]models.Ranking, 0, len(classificados))
+	
+	// Quantas linhas ocupam cada posição, para os empatados dividirem o prêmio
+	porPosicao := make(map[int]int, len(classificados))
+	for _, cl := range classificados {
+		porPosicao[cl.Posicao]++
 	}
 	
-	// Criar ranking geral
-	for i, rt := range rankingTemp {
+	for _, cl := range classificados {
 		ranking := models.Ranking{
-			EtapaID:          etapaID,
-			InscricaoID:      rt.Inscricao.ID.String(),
-			Posicao:          i + 1,
-			PontuacaoTotal:   rt.PontuacaoTotal,
-			MaiorPeixe:       rt.MaiorPeixe,
-			QuantidadePeixes: rt.QuantidadePeixes,
-			Categoria:        models.CategoriaGeral,
-		}
-		
-		// Definir premiação para os 3 primeiros
-		if i == 0 {
-			ranking.Premiacao = "1º Lugar"
-		} else if i == 1 {
-			ranking.Premiacao = "2º Lugar"
-		} else if i == 2 {
-			ranking.Premiacao = "3º Lugar"
+			EtapaID:           etapa.ID.String(),
+			InscricaoID:       cl.InscricaoID,
+			Posicao:           cl.Posicao,
+			Empatado:          cl.Empatado,
+			PontuacaoTotal:    cl.PontuacaoTotal,
+			MaiorPeixe:        cl.MaiorPeixe,
+			QuantidadePeixes:  cl.QuantidadePeixes,
+			Unidade:           etapa.Unidade(),
+			Categoria:         categoria,
+			Estrategia:        detalhes[cl.InscricaoID].Estrategia,
+			Explicacao:        detalhes[cl.InscricaoID].Explicacao,
+			CriterioDesempate: cl.CriterioDesempate,
 		}
-		
-		database.DB.Create(&ranking)
+	
+		// Premiação pela tabela da etapa (empatados dividem a colocação e o prêmio)
+		premios.Premiar(&ranking, porPosicao[cl.Posicao], nomeCategoria)
+	
+		rankings = append(rankings, ranking)
 	}
 	
-	// Gerar rankings de maiores peixes por espécie
-	gerarRankingMaiorPeixe(etapaID, models.EspecieTucunareAzul, models.CategoriaMaiorAzul)
-	gerarRankingMaiorPeixe(etapaID, models.EspecieTucunareAmarelo, models.CategoriaMaiorAmarelo)
-	gerarRankingMaiorPeixe(etapaID, models.EspecieTraira, models.CategoriaMaiorTraira)
+	if len(rankings) == 0 {
+		return nil
+	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"message":           "Ranking gerado com sucesso",
-		"total_competidores": len(rankingTemp),
-	})
+	return tx.Create(&rankings).Error
 }
 
-// gerarRankingMaiorPeixe gera ranking do maior peixe de uma espécie
-func gerarRankingMaiorPeixe(etapaID string, especie string, categoria string) {
+// gerarRankingMaiorPeixe gera ranking do maior peixe de uma espécie, pelo peso ou pelo comprimento
+func gerarRankingMaiorPeixe(tx *gorm.DB, etapa *models.Etapa, especie models.Especie, premios models.TabelaPremios) error {
+	ordem := "capturas.tamanho DESC"
+	if etapa.Medicao == models.MedicaoPeso {
+		ordem = "capturas.peso DESC"
+	}
+	
 	// Buscar a maior captura da espécie
 	var captura models.Captura
-	err := database.DB.
+	err := tx.
 		Joins("JOIN inscricoes ON capturas.inscricao_id = inscricoes.id").
 		Where("inscricoes.etapa_id = ? AND capturas.especie = ? AND capturas.validado = ? AND capturas.anulado = ?",
-			etapaID, especie, true, false).
-		Order("capturas.tamanho DESC").
-		Preload("Inscricao.Competidor").
+			etapa.ID, especie.Codigo, true, false).
+		Order(ordem).
 		First(&captura).Error
 	
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // Nenhuma captura desta espécie
+	}
 	if err != nil {
-		return // Nenhuma captura desta espécie
+		return err
 	}
 	
 	// Criar ranking
 	ranking := models.Ranking{
-		EtapaID:          etapaID,
+		EtapaID:          etapa.ID.String(),
 		InscricaoID:      captura.InscricaoID,
 		Posicao:          1,
 		PontuacaoTotal:   0,
-		MaiorPeixe:       captura.Tamanho,
+		MaiorPeixe:       captura.MedidaPontuada(),
+		Unidade:          etapa.Unidade(),
 		QuantidadePeixes: 1,
-		Categoria:        categoria,
-		Premiacao:        "Maior " + models.GetNomeEspecie(especie),
+		Categoria:        especie.CategoriaMaiorPeixe(),
 	}
 	
-	database.DB.Create(&ranking)
+	premios.Premiar(&ranking, 1, "Maior "+especie.NomeComum)
+	
+	return tx.Create(&ranking).Error
 }
 
-// ListarRankings retorna rankings com filtros
+// ListarRankings retorna rankings com filtros (por padrão a versão vigente de cada etapa).
+// Com ?format=csv|xlsx exporta planilha.
 func ListarRankings(c *gin.Context) {
 	etapaID := c.Query("etapa_id")
 	edicaoID := c.Query("edicao_id")
 	competidorID := c.Query("competidor_id")
 	
+	formato, ok := formatoExportacao(c)
+	if !ok {
+		return
+	}
+	
 	var rankings []models.Ranking
-	query := database.DB.Preload("Inscricao.Competidor").Preload("Etapa")
+	query, ok := filtrarVersaoRanking(database.DB.Preload("Inscricao.Competidor").Preload("Etapa"), c.Query("versao"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Versão inválida",
+		})
+		return
+	}
 	
 	if etapaID != "" {
 		query = query.Where("etapa_id = ?", etapaID)
@@ -218,6 +384,11 @@ func ListarRankings(c *gin.Context) {
 			Where("inscricoes.competidor_id = ?", competidorID)
 	}
 	
+	if formato != "" {
+		exportarRankings(c, query, formato)
+		return
+	}
+	
 	result := query.Order("posicao ASC").Find(&rankings)
 	
 	if result.Error != nil {
@@ -241,18 +412,25 @@ func DeletarRanking(c *gin.Context) {
 		return
 	}
 	
-	result := database.DB.Delete(&models.Ranking{}, "id = ?", id)
+	var ranking models.Ranking
+	if err := database.DB.First(&ranking, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ranking não encontrado",
+		})
+		return
+	}
 	
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Erro ao deletar ranking",
+	// Versões oficiais são imutáveis: corrigir gerando e publicando uma nova versão
+	if ranking.Oficial() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Ranking oficial publicado não pode ser removido; publique uma correção",
 		})
 		return
 	}
 	
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Ranking não encontrado",
+	if err := database.DB.Delete(&ranking).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar ranking",
 		})
 		return
 	}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListarRecursos retorna os recursos com filtros; competidores veem apenas os seus
+func ListarRecursos(c *gin.Context) {
+	status := c.Query("status")
+	etapaID := c.Query("etapa_id")
+
+	var recursos []models.Recurso
+	query := database.DB.Preload("Captura").Preload("Inscricao.Competidor").
+		Joins("JOIN inscricoes ON recursos.inscricao_id = inscricoes.id")
+
+	if c.GetString("tipo") == "competidor" {
+		query = query.Where("inscricoes.competidor_id = ?", c.GetString("user_id"))
+	}
+
+	if status != "" {
+		query = query.Where("recursos.status = ?", status)
+	}
+
+	if etapaID != "" {
+		query = query.Where("inscricoes.etapa_id = ?", etapaID)
+	}
+
+	result := query.Order("recursos.created_at ASC").Find(&recursos)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar recursos",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, recursos)
+}
+
+// BuscarRecurso retorna um recurso específico
+func BuscarRecurso(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var recurso models.Recurso
+	if err := database.DB.Preload("Captura.Penalidades").Preload("Inscricao.Competidor").
+		First(&recurso, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Recurso não encontrado",
+		})
+		return
+	}
+
+	if recurso.Inscricao == nil || !podeVerInscricao(c, recurso.Inscricao) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurso)
+}
+
+// CriarRecurso abre um recurso contra a anulação ou a penalidade de uma captura
+func CriarRecurso(c *gin.Context) {
+	var input struct {
+		CapturaID string `json:"captura_id" binding:"required,uuid"`
+		Motivo    string `json:"motivo" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao.Etapa").First(&captura, "id = ?", input.CapturaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return
+	}
+
+	if captura.Inscricao == nil || !podeVerInscricao(c, captura.Inscricao) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Captura pertence a outro competidor",
+		})
+		return
+	}
+
+	// Prazo conta a partir do fim da etapa
+	if etapa := captura.Inscricao.Etapa; etapa != nil {
+		prazo, err := etapa.PrazoRecurso()
+		if err == nil && time.Now().After(prazo) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Prazo para recurso encerrado em " + prazo.Format("02/01/2006 15:04"),
+			})
+			return
+		}
+	}
+
+	var pendentes int64
+	database.DB.Model(&models.Recurso{}).
+		Where("captura_id = ? AND status IN ?", captura.ID, []string{models.StatusRecursoAberto, models.StatusRecursoEmAnalise}).
+		Count(&pendentes)
+
+	if pendentes > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Já existe um recurso pendente para esta captura",
+		})
+		return
+	}
+
+	recurso, err := models.NovoRecurso(&captura, input.Motivo, c.GetString("nome"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := database.DB.Create(recurso).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao abrir recurso: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurso)
+}
+
+// AnalisarRecurso marca o recurso como em análise pelo organizador
+func AnalisarRecurso(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var recurso models.Recurso
+	if err := database.DB.First(&recurso, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Recurso não encontrado",
+		})
+		return
+	}
+
+	if recurso.Status != models.StatusRecursoAberto {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Recurso não está aberto",
+		})
+		return
+	}
+
+	recurso.IniciarAnalise(c.GetString("nome"))
+	database.DB.Save(&recurso)
+
+	c.JSON(http.StatusOK, recurso)
+}
+
+// DecidirRecurso defere ou indefere um recurso. Deferido, desfaz a anulação ou a
+// penalidade, recalcula a pontuação da inscrição e refaz o ranking da etapa.
+func DecidirRecurso(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var input struct {
+		Deferido *bool  `json:"deferido" binding:"required"`
+		Decisao  string `json:"decisao" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	var recurso models.Recurso
+	if err := database.DB.Preload("Captura").First(&recurso, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Recurso não encontrado",
+		})
+		return
+	}
+
+	if !recurso.EstaPendente() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Recurso já foi decidido",
+		})
+		return
+	}
+
+	recurso.Decidir(*input.Deferido, input.Decisao, c.GetString("nome"))
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&recurso).Error; err != nil {
+			return err
+		}
+
+		if !*input.Deferido || recurso.Captura == nil {
+			return nil
+		}
+
+		recurso.Reverter(recurso.Captura)
+		if err := tx.Omit(clause.Associations).Save(recurso.Captura).Error; err != nil {
+			return err
+		}
+
+		// Penalidade revertida: o detalhamento do catálogo deixa de valer
+		if recurso.Tipo == models.TipoRecursoPenalidade {
+			return tx.Where("captura_id = ?", recurso.CapturaID).Delete(&models.CapturaPenalidade{}).Error
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao registrar decisão: " + err.Error(),
+		})
+		return
+	}
+
+	if *input.Deferido {
+		reprocessarInscricao(recurso.InscricaoID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurso " + recurso.Status,
+		"recurso": recurso,
+	})
+}
+
+// reprocessarInscricao recalcula a pontuação e, se a etapa já tem ranking, gera de novo o provisório
+func reprocessarInscricao(inscricaoID string) {
+	atualizarPontuacaoInscricao(inscricaoID)
+
+	var inscricao models.Inscricao
+	if err := database.DB.First(&inscricao, "id = ?", inscricaoID).Error; err != nil {
+		return
+	}
+
+	var rankings int64
+	database.DB.Model(&models.Ranking{}).Where("etapa_id = ? AND versao = ?", inscricao.EtapaID, 0).Count(&rankings)
+	if rankings > 0 {
+		total, err := gerarRankingEtapa(inscricao.EtapaID)
+		if err != nil {
+			logrus.Errorf("Erro ao refazer o ranking da etapa %s após recurso: %v", inscricao.EtapaID, err)
+			return
+		}
+		logrus.Infof("Ranking da etapa %s refeito após recurso (%d competidores)", inscricao.EtapaID, total)
+	}
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListarRegrasEspecie retorna as regras de tamanho cadastradas, com filtros opcionais
+func ListarRegrasEspecie(c *gin.Context) {
+	edicaoID := c.Query("edicao_id")
+	modalidadeID := c.Query("modalidade_id")
+	etapaID := c.Query("etapa_id")
+	especie := c.Query("especie")
+
+	var regras []models.RegraEspecie
+	query := database.DB.Model(&models.RegraEspecie{})
+
+	if edicaoID != "" {
+		query = query.Where("edicao_id = ?", edicaoID)
+	}
+
+	if modalidadeID != "" {
+		query = query.Where("modalidade_id = ?", modalidadeID)
+	}
+
+	if etapaID != "" {
+		query = query.Where("etapa_id = ?", etapaID)
+	}
+
+	if especie != "" {
+		query = query.Where("especie = ?", especie)
+	}
+
+	result := query.Order("especie ASC").Find(&regras)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar regras",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, regras)
+}
+
+// BuscarRegrasEtapa retorna a regra em vigor para cada espécie em uma etapa
+func BuscarRegrasEtapa(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var etapa models.Etapa
+	if err := database.DB.First(&etapa, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Etapa não encontrada",
+		})
+		return
+	}
+
+	regras := carregarRegras(database.DB, &etapa)
+
+	especies := carregarEspecies(database.DB, etapa.EdicaoID.String()).Ativas()
+
+	vigentes := make([]models.RegraEspecie, 0, len(especies))
+	for _, especie := range especies {
+		vigentes = append(vigentes, regras.Para(especie.Codigo))
+	}
+
+	c.JSON(http.StatusOK, vigentes)
+}
+
+// CriarRegraEspecie cadastra uma regra para uma edição, modalidade ou etapa
+func CriarRegraEspecie(c *gin.Context) {
+	var regra models.RegraEspecie
+
+	if err := c.ShouldBindJSON(&regra); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	if !validarRegraEspecie(c, &regra) {
+		return
+	}
+
+	if err := database.DB.Create(&regra).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar regra: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, regra)
+}
+
+// AtualizarRegraEspecie altera limites de uma regra; não recalcula pontuações já feitas
+func AtualizarRegraEspecie(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var regra models.RegraEspecie
+	if err := database.DB.First(&regra, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Regra não encontrada",
+		})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&regra); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	if !validarRegraEspecie(c, &regra) {
+		return
+	}
+
+	database.DB.Save(&regra)
+
+	c.JSON(http.StatusOK, regra)
+}
+
+// DeletarRegraEspecie remove uma regra (soft delete)
+func DeletarRegraEspecie(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	result := database.DB.Delete(&models.RegraEspecie{}, "id = ?", id)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao deletar regra",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Regra não encontrada",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Regra deletada com sucesso",
+	})
+}
+
+// validarRegraEspecie exige exatamente um escopo, espécie válida e limites coerentes.
+// Em caso de erro a resposta já é enviada e retorna false.
+func validarRegraEspecie(c *gin.Context, regra *models.RegraEspecie) bool {
+	escopos := 0
+	for _, chave := range []*uuid.UUID{regra.EdicaoID, regra.ModalidadeID, regra.EtapaID} {
+		if chave != nil {
+			escopos++
+		}
+	}
+
+	if escopos != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Informe apenas um entre edicao_id, modalidade_id e etapa_id",
+		})
+		return false
+	}
+
+	if !especieConhecida(regra) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Espécie inválida",
+		})
+		return false
+	}
+
+	if regra.TamanhoMaximo > 0 && regra.TamanhoMaximo < regra.TamanhoMinimo {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tamanho máximo computado não pode ser menor que o mínimo",
+		})
+		return false
+	}
+
+	var count int64
+	database.DB.Model(&models.RegraEspecie{}).
+		Where("especie = ? AND id <> ?", regra.Especie, regra.ID).
+		Where("edicao_id IS NOT DISTINCT FROM ? AND modalidade_id IS NOT DISTINCT FROM ? AND etapa_id IS NOT DISTINCT FROM ?",
+			regra.EdicaoID, regra.ModalidadeID, regra.EtapaID).
+		Count(&count)
+
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Já existe regra para esta espécie neste escopo",
+		})
+		return false
+	}
+
+	return true
+}
+
+// carregarRegras busca as regras que podem valer na etapa e resolve a de cada espécie
+func carregarRegras(db *gorm.DB, etapa *models.Etapa) models.RegrasEspecie {
+	if etapa == nil {
+		return nil
+	}
+
+	var regras []models.RegraEspecie
+	db.Where("etapa_id = ? OR modalidade_id = ? OR edicao_id = ?", etapa.ID, etapa.ModalidadeID, etapa.EdicaoID).
+		Find(&regras)
+
+	return models.ResolverRegras(etapa, regras)
+}
+
+// especieConhecida confere a espécie da regra no catálogo da edição a que ela se aplica.
+// Modalidades valem para todas as edições: basta a espécie existir em algum catálogo.
+func especieConhecida(regra *models.RegraEspecie) bool {
+	edicaoID := ""
+	switch {
+	case regra.EdicaoID != nil:
+		edicaoID = regra.EdicaoID.String()
+	case regra.EtapaID != nil:
+		var etapa models.Etapa
+		if err := database.DB.First(&etapa, "id = ?", regra.EtapaID).Error; err != nil {
+			return false
+		}
+		edicaoID = etapa.EdicaoID.String()
+	}
+
+	if edicaoID != "" {
+		return carregarEspecies(database.DB, edicaoID).ValidarEspecie(regra.Especie)
+	}
+
+	if models.EspeciesPadrao().ValidarEspecie(regra.Especie) {
+		return true
+	}
+
+	var count int64
+	database.DB.Model(&models.Especie{}).Where("codigo = ? AND ativa = ?", regra.Especie, true).Count(&count)
+	return count > 0
+}
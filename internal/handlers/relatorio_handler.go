@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/pdf"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportarRankingEtapaPDF gera o PDF da classificação de uma etapa em uma categoria,
+// no formato das planilhas publicadas pela organização
+func ExportarRankingEtapaPDF(c *gin.Context) {
+	etapaID := c.Param("id")
+	categoria := c.DefaultQuery("categoria", models.CategoriaGeral)
+	versao := c.Query("versao") // vazio = vigente, provisorio ou número da versão oficial
+
+	if _, err := uuid.Parse(etapaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var etapa models.Etapa
+	if err := database.DB.Preload("Edicao").Preload("Modalidade").First(&etapa, "id = ?", etapaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Etapa não encontrada",
+		})
+		return
+	}
+
+	query, ok := filtrarVersaoRanking(database.DB.Where("etapa_id = ? AND categoria = ?", etapaID, categoria), versao)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Versão inválida",
+		})
+		return
+	}
+
+	var rankings []models.Ranking
+	if err := query.Preload("Inscricao.Competidor").Order("posicao ASC").Find(&rankings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar ranking",
+		})
+		return
+	}
+
+	if len(rankings) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ranking não encontrado para a categoria",
+		})
+		return
+	}
+
+	local, err := etapa.Localizacao()
+	if err != nil {
+		local = time.Local
+	}
+
+	subtitulos := []string{}
+	if etapa.Edicao != nil {
+		subtitulos = append(subtitulos, fmt.Sprintf("%s - %d", etapa.Edicao.Nome, etapa.Edicao.Ano))
+	}
+	subtitulos = append(subtitulos, fmt.Sprintf("%dª Etapa - %s - %s - %s",
+		etapa.Numero, etapa.Nome, etapa.Local, etapa.DataLargada.Format("02/01/2006")))
+	if etapa.Modalidade != nil {
+		subtitulos = append(subtitulos, "Modalidade: "+etapa.Modalidade.Nome)
+	}
+	subtitulos = append(subtitulos, "Categoria: "+nomeCategoriaRanking(etapa.EdicaoID.String(), categoria))
+	subtitulos = append(subtitulos, situacaoRanking(etapaID, rankings[0].Versao, local))
+
+	secao := pdf.Secao{
+		Colunas: []pdf.Coluna{
+			{Titulo: "Pos", Peso: 5, Alinhamento: pdf.Centro},
+			{Titulo: "Competidor", Peso: 28},
+			{Titulo: "Cidade/UF", Peso: 18},
+			{Titulo: "Peixes", Peso: 7, Alinhamento: pdf.Centro},
+			{Titulo: "Maior peixe", Peso: 11, Alinhamento: pdf.Direita},
+			{Titulo: "Pontuação", Peso: 11, Alinhamento: pdf.Direita},
+			{Titulo: "Premiação", Peso: 20},
+		},
+	}
+
+	for _, ranking := range rankings {
+		posicao := fmt.Sprintf("%dº", ranking.Posicao)
+		if ranking.Empatado {
+			posicao += " (E)"
+		}
+
+		nome, cidade := "", ""
+		if ranking.Inscricao != nil && ranking.Inscricao.Competidor != nil {
+			nome = ranking.Inscricao.Competidor.Nome
+			cidade = ranking.Inscricao.Competidor.Cidade
+			if ranking.Inscricao.Competidor.Estado != "" {
+				cidade += "/" + ranking.Inscricao.Competidor.Estado
+			}
+		}
+
+		secao.Linhas = append(secao.Linhas, []string{
+			posicao,
+			nome,
+			cidade,
+			fmt.Sprintf("%d", ranking.QuantidadePeixes),
+			formatarNumero(ranking.MaiorPeixe) + " " + ranking.Unidade,
+			formatarNumero(ranking.PontuacaoTotal),
+			ranking.Premiacao,
+		})
+		secao.Destaque = append(secao.Destaque, ranking.Premiado())
+	}
+
+	relatorio := pdf.Relatorio{
+		Titulo:     "CLASSIFICAÇÃO - " + strings.ToUpper(etapa.Nome),
+		Subtitulos: subtitulos,
+		Secoes:     []pdf.Secao{secao},
+	}
+	if etapa.Edicao != nil {
+		relatorio.Rodape = etapa.Edicao.Patrocinadores
+	}
+
+	nomeArquivo := fmt.Sprintf("classificacao-etapa-%d-%s.pdf", etapa.Numero, categoria)
+	enviarPDF(c, &relatorio, nomeArquivo)
+}
+
+// ExportarClassificacaoEdicaoPDF gera o PDF da classificação da temporada, uma seção por modalidade
+func ExportarClassificacaoEdicaoPDF(c *gin.Context) {
+	id := c.Param("id")
+	modalidadeID := c.Query("modalidade_id")
+	categoria := c.DefaultQuery("categoria", models.CategoriaGeral)
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var edicao models.Edicao
+	if err := database.DB.First(&edicao, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Edição não encontrada",
+		})
+		return
+	}
+
+	tabela, err := edicao.TabelaPontosTemporada()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	modalidades, err := montarClassificacaoEdicao(&edicao, modalidadeID, categoria, tabela)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao buscar etapas",
+		})
+		return
+	}
+
+	if len(modalidades) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Nenhuma etapa classificada na edição",
+		})
+		return
+	}
+
+	subtitulos := []string{
+		fmt.Sprintf("%s - %d", edicao.Nome, edicao.Ano),
+		"Categoria: " + nomeCategoriaRanking(edicao.ID.String(), categoria),
+	}
+	if edicao.DescartarPiores > 0 {
+		subtitulos = append(subtitulos, fmt.Sprintf("Descartados os %d piores resultados de cada competidor (entre parênteses)", edicao.DescartarPiores))
+	}
+
+	relatorio := pdf.Relatorio{
+		Titulo:     "CLASSIFICAÇÃO GERAL " + strings.ToUpper(edicao.Nome),
+		Subtitulos: subtitulos,
+		Rodape:     edicao.Patrocinadores,
+		Paisagem:   true,
+	}
+
+	for _, modalidade := range modalidades {
+		secao := pdf.Secao{
+			Titulo: modalidade.Modalidade,
+			Colunas: []pdf.Coluna{
+				{Titulo: "Pos", Peso: 5, Alinhamento: pdf.Centro},
+				{Titulo: "Competidor", Peso: 26},
+			},
+		}
+		for _, etapa := range modalidade.Etapas {
+			secao.Colunas = append(secao.Colunas, pdf.Coluna{Titulo: fmt.Sprintf("E%d", etapa.Numero), Peso: 7, Alinhamento: pdf.Direita})
+		}
+		secao.Colunas = append(secao.Colunas,
+			pdf.Coluna{Titulo: "Vitórias", Peso: 7, Alinhamento: pdf.Centro},
+			pdf.Coluna{Titulo: "Pontos", Peso: 9, Alinhamento: pdf.Direita},
+		)
+
+		for _, linha := range modalidade.Classificacao {
+			posicao := fmt.Sprintf("%dº", linha.Posicao)
+			if linha.Empatado {
+				posicao += " (E)"
+			}
+
+			valores := []string{posicao, linha.Nome}
+			for _, etapa := range modalidade.Etapas {
+				valores = append(valores, pontosEtapa(linha.Resultados, etapa.ID))
+			}
+			valores = append(valores, fmt.Sprintf("%d", linha.Vitorias), formatarNumero(linha.Pontos))
+
+			secao.Linhas = append(secao.Linhas, valores)
+			secao.Destaque = append(secao.Destaque, linha.Posicao <= 3)
+		}
+
+		relatorio.Secoes = append(relatorio.Secoes, secao)
+	}
+
+	nomeArquivo := fmt.Sprintf("classificacao-%d-%s.pdf", edicao.Ano, categoria)
+	enviarPDF(c, &relatorio, nomeArquivo)
+}
+
+// enviarPDF gera o relatório e o envia para exibição no navegador
+func enviarPDF(c *gin.Context, relatorio *pdf.Relatorio, nomeArquivo string) {
+	conteudo, err := relatorio.Gerar()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao gerar PDF",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", nomeArquivo))
+	c.Data(http.StatusOK, "application/pdf", conteudo)
+}
+
+// situacaoRanking descreve a versão impressa: oficial (com data e responsável) ou provisória
+func situacaoRanking(etapaID string, versao int, local *time.Location) string {
+	if versao == 0 {
+		return "Resultado provisório, sujeito a alteração"
+	}
+
+	var publicacao models.PublicacaoRanking
+	if err := database.DB.First(&publicacao, "etapa_id = ? AND versao = ?", etapaID, versao).Error; err != nil {
+		return fmt.Sprintf("Resultado oficial - versão %d", versao)
+	}
+
+	situacao := fmt.Sprintf("Resultado oficial - versão %d, publicado em %s",
+		versao, publicacao.PublicadoEm.In(local).Format("02/01/2006 15:04"))
+	if publicacao.PublicadoPor != "" {
+		situacao += " por " + publicacao.PublicadoPor
+	}
+	return situacao
+}
+
+// nomeCategoriaRanking retorna o nome de exibição de uma categoria do ranking
+func nomeCategoriaRanking(edicaoID, categoria string) string {
+	if categoria == models.CategoriaGeral {
+		return "Geral"
+	}
+
+	for _, especie := range carregarEspecies(database.DB, edicaoID) {
+		if especie.CategoriaMaiorPeixe() == categoria {
+			return "Maior " + especie.NomeComum
+		}
+	}
+
+	var categoriaCompetidor models.CategoriaCompetidor
+	if err := database.DB.First(&categoriaCompetidor, "edicao_id = ? AND codigo = ?", edicaoID, categoria).Error; err == nil {
+		return categoriaCompetidor.Nome
+	}
+	return categoria
+}
+
+// pontosEtapa formata os pontos do competidor na etapa; descartados entre parênteses, ausência como "-"
+func pontosEtapa(resultados []models.ResultadoEtapa, etapaID string) string {
+	for _, resultado := range resultados {
+		if resultado.EtapaID != etapaID || resultado.Posicao == 0 {
+			continue
+		}
+		if resultado.Descartado {
+			return "(" + formatarNumero(resultado.Pontos) + ")"
+		}
+		return formatarNumero(resultado.Pontos)
+	}
+	return "-"
+}
+
+// formatarNumero escreve o número com duas casas e vírgula decimal, como nas planilhas impressas
+func formatarNumero(valor float64) string {
+	return strings.Replace(fmt.Sprintf("%.2f", valor), ".", ",", 1)
+}
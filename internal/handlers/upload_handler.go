@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/database"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/models"
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/storage"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Envio resumível de vídeos pelo protocolo tus 1.0.0 (https://tus.io/protocols/resumable-upload)
+const (
+	versaoTus   = "1.0.0"
+	extensaoTus = "creation,termination,expiration"
+)
+
+// OpcoesUpload informa as capacidades do servidor tus
+func OpcoesUpload(c *gin.Context) {
+	c.Header("Tus-Resumable", versaoTus)
+	c.Header("Tus-Version", versaoTus)
+	c.Header("Tus-Extension", extensaoTus)
+	c.Header("Tus-Max-Size", strconv.FormatInt(int64(config.AppConfig.Storage.MaxVideoMB)<<20, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// CriarUpload inicia um envio resumível do vídeo de uma captura.
+// Upload-Metadata deve conter captura_id (e opcionalmente filename).
+func CriarUpload(c *gin.Context) {
+	if !verificarVersaoTus(c) {
+		return
+	}
+
+	tamanho, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || tamanho <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Cabeçalho Upload-Length inválido",
+		})
+		return
+	}
+
+	limite := int64(config.AppConfig.Storage.MaxVideoMB) << 20
+	if tamanho > limite {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": "Vídeo excede o tamanho máximo permitido",
+		})
+		return
+	}
+
+	metadados := lerMetadadosTus(c.GetHeader("Upload-Metadata"))
+	capturaID := metadados["captura_id"]
+	if _, err := uuid.Parse(capturaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "captura_id ausente ou inválido em Upload-Metadata",
+		})
+		return
+	}
+
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao").First(&captura, "id = ?", capturaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return
+	}
+
+	if !podeAlterarCaptura(c, &captura) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Captura pertence a outro competidor",
+		})
+		return
+	}
+
+	if captura.Validado || captura.Anulado {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Vídeo não pode ser substituído após validação ou anulação",
+		})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	upload := models.Upload{
+		CapturaID:   capturaID,
+		EnviadoPor:  userID.(string),
+		NomeArquivo: metadados["filename"],
+		Tamanho:     tamanho,
+		ExpiraEm:    time.Now().Add(time.Duration(config.AppConfig.Storage.UploadExpiraH) * time.Hour),
+	}
+
+	if err := database.DB.Create(&upload).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao criar upload: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", "/api/uploads/"+upload.ID.String())
+	c.Header("Upload-Expires", upload.ExpiraEm.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// ConsultarUpload informa quantos bytes já foram recebidos (HEAD)
+func ConsultarUpload(c *gin.Context) {
+	if !verificarVersaoTus(c) {
+		return
+	}
+
+	upload, ok := buscarUpload(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Recebido, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Tamanho, 10))
+	c.Header("Upload-Expires", upload.ExpiraEm.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusOK)
+}
+
+// EnviarParteUpload recebe o próximo trecho do arquivo (PATCH) e o grava no storage
+func EnviarParteUpload(c *gin.Context) {
+	if !verificarVersaoTus(c) {
+		return
+	}
+
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Content-Type deve ser application/offset+octet-stream",
+		})
+		return
+	}
+
+	upload, ok := buscarUpload(c)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Cabeçalho Upload-Offset inválido",
+		})
+		return
+	}
+
+	if upload.Concluido || offset != upload.Recebido {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Upload-Offset não confere com o servidor",
+			"offset": upload.Recebido,
+		})
+		return
+	}
+
+	// Todos os bytes já chegaram mas a conclusão falhou antes: o novo PATCH tenta concluir de novo
+	if upload.Recebido == upload.Tamanho {
+		if !concluirUpload(c, upload) {
+			return
+		}
+		c.Header("Upload-Offset", strconv.FormatInt(upload.Recebido, 10))
+		c.Header("Upload-Expires", upload.ExpiraEm.UTC().Format(http.TimeFormat))
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	// O ReadTimeout do servidor é curto demais para trechos grandes em rede móvel
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Now().Add(tempoMaximoUpload))
+
+	// O trecho vai para um temporário: se a conexão cair, o que chegou é aproveitado
+	tmp, err := os.CreateTemp("", "tus-parte-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao receber trecho",
+		})
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	recebidos, erroLeitura := io.Copy(tmp, io.LimitReader(c.Request.Body, upload.Tamanho-upload.Recebido))
+	if recebidos == 0 {
+		if erroLeitura != nil {
+			logrus.Warnf("Upload %s: conexão encerrada sem dados: %v", upload.ID, erroLeitura)
+			return
+		}
+		c.Header("Upload-Offset", strconv.FormatInt(upload.Recebido, 10))
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao receber trecho",
+		})
+		return
+	}
+
+	// Mesmo com o cliente desconectado, a parte recebida precisa ser gravada
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	chave := upload.NovaChaveParte(offset, uuid.NewString()[:8])
+	if err := storage.Arquivos.Put(ctx, chave, tmp, recebidos, "application/octet-stream"); err != nil {
+		logrus.Errorf("Upload %s: erro ao gravar parte: %v", upload.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar trecho",
+		})
+		return
+	}
+
+	// Avançar o offset só se ninguém gravou este trecho antes (PATCH concorrente)
+	result := database.DB.Model(&models.Upload{}).
+		Where("id = ? AND recebido = ?", upload.ID, offset).
+		Updates(map[string]interface{}{
+			"recebido": offset + recebidos,
+			"partes":   gorm.Expr("COALESCE(partes, '') || ?", chave+"\n"),
+		})
+
+	if result.Error != nil || result.RowsAffected == 0 {
+		_ = storage.Arquivos.Delete(ctx, chave)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro ao atualizar upload",
+			})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Trecho já recebido por outra requisição",
+		})
+		return
+	}
+
+	upload.Recebido = offset + recebidos
+	upload.Partes += chave + "\n"
+
+	if erroLeitura != nil {
+		logrus.Warnf("Upload %s: conexão interrompida em %d bytes: %v", upload.ID, upload.Recebido, erroLeitura)
+		return
+	}
+
+	if upload.Recebido == upload.Tamanho {
+		if !concluirUpload(c, upload) {
+			return
+		}
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Recebido, 10))
+	c.Header("Upload-Expires", upload.ExpiraEm.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+}
+
+// CancelarUpload descarta um envio e as partes já recebidas (DELETE)
+func CancelarUpload(c *gin.Context) {
+	if !verificarVersaoTus(c) {
+		return
+	}
+
+	upload, ok := buscarUpload(c)
+	if !ok {
+		return
+	}
+
+	removerPartesUpload(c.Request.Context(), upload)
+	database.DB.Unscoped().Delete(upload)
+
+	c.Status(http.StatusNoContent)
+}
+
+// LimparUploadsExpirados remove periodicamente envios não concluídos dentro do prazo
+func LimparUploadsExpirados(ctx context.Context, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		limparUploads(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// limparUploads apaga partes e registros de uploads vencidos
+func limparUploads(ctx context.Context) {
+	var uploads []models.Upload
+	if err := database.DB.Where("expira_em < ?", time.Now()).Find(&uploads).Error; err != nil {
+		logrus.Errorf("Erro ao buscar uploads expirados: %v", err)
+		return
+	}
+
+	for i := range uploads {
+		removerPartesUpload(ctx, &uploads[i])
+		database.DB.Unscoped().Delete(&uploads[i])
+	}
+
+	if len(uploads) > 0 {
+		logrus.Infof("🧹 %d upload(s) expirado(s) removido(s)", len(uploads))
+	}
+}
+
+// concluirUpload junta as partes, confere se é vídeo e anexa à captura.
+// Em caso de erro a resposta já é enviada e retorna false.
+func concluirUpload(c *gin.Context, upload *models.Upload) bool {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	// Juntar as partes: a leitura dos metadados precisa de acesso aleatório ao arquivo
+	arquivo, err := os.CreateTemp("", "tus-video-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao montar vídeo",
+		})
+		return false
+	}
+	defer os.Remove(arquivo.Name())
+	defer arquivo.Close()
+
+	for _, chave := range upload.ChavesPartes() {
+		parte, err := storage.Arquivos.Get(ctx, chave)
+		if err != nil {
+			logrus.Errorf("Upload %s: parte %s indisponível: %v", upload.ID, chave, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro ao montar vídeo",
+			})
+			return false
+		}
+		_, err = io.Copy(arquivo, parte)
+		parte.Close()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro ao montar vídeo",
+			})
+			return false
+		}
+	}
+
+	if _, err := arquivo.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao montar vídeo",
+		})
+		return false
+	}
+
+	tipo, err := mimetype.DetectReader(arquivo)
+	if err != nil || !ehVideo(tipo) {
+		removerPartesUpload(ctx, upload)
+		database.DB.Unscoped().Delete(upload)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": models.ErrVideoInvalido,
+			"tipo":  tipoDetectado(tipo),
+		})
+		return false
+	}
+
+	if _, err := arquivo.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao montar vídeo",
+		})
+		return false
+	}
+
+	var captura models.Captura
+	if err := database.DB.Preload("Inscricao.Etapa").First(&captura, "id = ?", upload.CapturaID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Captura não encontrada",
+		})
+		return false
+	}
+
+	// A captura pode ter sido validada ou anulada durante o envio
+	if captura.Validado || captura.Anulado {
+		removerPartesUpload(ctx, upload)
+		database.DB.Unscoped().Delete(upload)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Vídeo não pode ser substituído após validação ou anulação",
+		})
+		return false
+	}
+
+	if err := anexarVideo(ctx, &captura, arquivo, upload.Tamanho, tipo); err != nil {
+		logrus.Errorf("Upload %s: erro ao anexar vídeo à captura %s: %v", upload.ID, captura.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Erro ao armazenar vídeo",
+		})
+		return false
+	}
+
+	removerPartesUpload(ctx, upload)
+	upload.Concluido = true
+	upload.Partes = ""
+	database.DB.Model(upload).Updates(map[string]interface{}{
+		"concluido": true,
+		"partes":    "",
+	})
+
+	return true
+}
+
+// buscarUpload carrega o upload do parâmetro :id, conferindo dono e validade.
+// Em caso de erro a resposta já é enviada e ok retorna false.
+func buscarUpload(c *gin.Context) (*models.Upload, bool) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return nil, false
+	}
+
+	var upload models.Upload
+	if err := database.DB.First(&upload, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Upload não encontrado",
+		})
+		return nil, false
+	}
+
+	userID, _ := c.Get("user_id")
+	if upload.EnviadoPor != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": models.ErrPermissaoNegada,
+		})
+		return nil, false
+	}
+
+	if upload.EstaExpirado() {
+		c.JSON(http.StatusGone, gin.H{
+			"error": "Upload expirado",
+		})
+		return nil, false
+	}
+
+	return &upload, true
+}
+
+// removerPartesUpload apaga do storage as partes de um upload
+func removerPartesUpload(ctx context.Context, upload *models.Upload) {
+	for _, chave := range upload.ChavesPartes() {
+		if err := storage.Arquivos.Delete(ctx, chave); err != nil {
+			logrus.Warnf("Upload %s: erro ao remover parte %s: %v", upload.ID, chave, err)
+		}
+	}
+}
+
+// verificarVersaoTus exige Tus-Resumable compatível e o devolve na resposta
+func verificarVersaoTus(c *gin.Context) bool {
+	c.Header("Tus-Resumable", versaoTus)
+
+	if c.GetHeader("Tus-Resumable") != versaoTus {
+		c.Header("Tus-Version", versaoTus)
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error": "Versão do protocolo tus não suportada",
+		})
+		return false
+	}
+
+	return true
+}
+
+// lerMetadadosTus decodifica Upload-Metadata ("chave base64,chave base64")
+func lerMetadadosTus(cabecalho string) map[string]string {
+	metadados := map[string]string{}
+
+	for _, par := range strings.Split(cabecalho, ",") {
+		partes := strings.Fields(par)
+		if len(partes) == 0 {
+			continue
+		}
+
+		valor := ""
+		if len(partes) > 1 {
+			if decodificado, err := base64.StdEncoding.DecodeString(partes[1]); err == nil {
+				valor = string(decodificado)
+			}
+		}
+		metadados[partes[0]] = valor
+	}
+
+	return metadados
+}
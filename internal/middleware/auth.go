@@ -15,6 +15,14 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		// Obter token do header Authorization
 		authHeader := c.GetHeader("Authorization")
 
+		// Navegadores não enviam Authorization no WebSocket: o token vem no subprotocolo "bearer, {token}"
+		if authHeader == "" && strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			protocolos := strings.Split(c.GetHeader("Sec-WebSocket-Protocol"), ",")
+			if len(protocolos) == 2 && strings.EqualFold(strings.TrimSpace(protocolos[0]), "bearer") {
+				authHeader = "Bearer " + strings.TrimSpace(protocolos[1])
+			}
+		}
+
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Token de autenticação não fornecido",
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // Captura representa cada peixe capturado
 type Captura struct {
@@ -8,22 +11,53 @@ type Captura struct {
 	InscricaoID      string     `gorm:"type:uuid;not null;index" json:"inscricao_id" binding:"required"`
 	Inscricao        *Inscricao `gorm:"foreignKey:InscricaoID" json:"inscricao,omitempty"`
 	Especie          string     `gorm:"size:30;not null;index" json:"especie" binding:"required"`
-	TamanhoOriginal  float64    `gorm:"type:decimal(10,2);not null" json:"tamanho_original" binding:"required,min=0"`
+	TamanhoOriginal  float64    `gorm:"type:decimal(10,2);not null" json:"tamanho_original" binding:"min=0"` // obrigatório se a etapa mede comprimento
 	Tamanho          float64    `gorm:"type:decimal(10,2);not null" json:"tamanho"`
-	VideoURL         string     `gorm:"size:500;not null" json:"video_url" binding:"required"`
+	PesoOriginal     float64    `gorm:"type:decimal(10,2);default:0" json:"peso_original" binding:"min=0"` // em g; obrigatório se a etapa mede peso
+	Peso             float64    `gorm:"type:decimal(10,2);default:0" json:"peso"`
+	Unidade          string     `gorm:"size:5;default:'cm'" json:"unidade"` // unidade da medida pontuada e da penalidade
+	VideoURL         string     `gorm:"size:500;not null" json:"video_url"`
+	VideoArquivo     string     `gorm:"size:500" json:"-"` // chave do vídeo no storage
 	ThumbnailURL     string     `gorm:"size:500" json:"thumbnail_url"`
 	DuracaoVideo     int        `json:"duracao_video"`
 	TamanhoArquivo   int64      `json:"tamanho_arquivo"`
+	DataGravacao     *time.Time `json:"data_gravacao,omitempty"` // lida do cabeçalho do vídeo
+	LarguraVideo     int        `json:"largura_video"`
+	AlturaVideo      int        `json:"altura_video"`
+	VideoCurto       bool       `gorm:"default:false" json:"video_curto"`
+	GravadoForaEtapa bool       `gorm:"default:false" json:"gravado_fora_etapa"`
+	NumeroReguaVideo int        `json:"numero_regua_video"` // número da régua lido pelo fiscal no vídeo
+	ReguaConfere     bool       `gorm:"default:false" json:"regua_confere"`
+	ReservadaPor     *string    `gorm:"type:uuid;index" json:"reservada_por,omitempty"` // fiscal que pegou a captura na fila
+	ReservadaAte     *time.Time `json:"reservada_ate,omitempty"`
 	Validado         bool       `gorm:"default:false;index" json:"validado"`
 	ValidadoPor      string     `gorm:"size:100" json:"validado_por,omitempty"`
 	DataValidacao    *time.Time `json:"data_validacao,omitempty"`
 	Penalidade       float64    `gorm:"type:decimal(10,2);default:0" json:"penalidade"`
 	MotivoPenalidade string     `gorm:"type:text" json:"motivo_penalidade,omitempty"`
-	Anulado          bool       `gorm:"default:false;index" json:"anulado"`
-	MotivoAnulacao   string     `gorm:"type:text" json:"motivo_anulacao,omitempty"`
-	HoraCaptura      time.Time  `gorm:"not null;index" json:"hora_captura"`
-	Observacoes      string     `gorm:"type:text" json:"observacoes,omitempty"`
-	ContaCota        bool       `gorm:"default:true" json:"conta_cota"`
+	TamanhoMedido    float64    `gorm:"type:decimal(10,2);default:0" json:"tamanho_medido"` // medido pelo fiscal; 0 = vale o informado
+	PesoMedido       float64    `gorm:"type:decimal(10,2);default:0" json:"peso_medido"`    // pesado pelo fiscal; 0 = vale o informado
+
+	// Validação dupla: medições independentes de dois fiscais, na unidade da captura
+	StatusValidacao   string     `gorm:"size:20;default:'pendente';index" json:"status_validacao"`
+	Fiscal1ID         *string    `gorm:"type:uuid" json:"fiscal1_id,omitempty"`
+	Fiscal1Nome       string     `gorm:"size:100" json:"fiscal1_nome,omitempty"`
+	Fiscal1Tamanho    float64    `gorm:"type:decimal(10,2)" json:"fiscal1_tamanho,omitempty"`
+	Fiscal1Penalidade float64    `gorm:"type:decimal(10,2)" json:"fiscal1_penalidade,omitempty"`
+	Fiscal1Em         *time.Time `json:"fiscal1_em,omitempty"`
+	Fiscal2ID         *string    `gorm:"type:uuid" json:"fiscal2_id,omitempty"`
+	Fiscal2Nome       string     `gorm:"size:100" json:"fiscal2_nome,omitempty"`
+	Fiscal2Tamanho    float64    `gorm:"type:decimal(10,2)" json:"fiscal2_tamanho,omitempty"`
+	Fiscal2Penalidade float64    `gorm:"type:decimal(10,2)" json:"fiscal2_penalidade,omitempty"`
+	Fiscal2Em         *time.Time `json:"fiscal2_em,omitempty"`
+
+	Anulado        bool                `gorm:"default:false;index" json:"anulado"`
+	MotivoAnulacao string              `gorm:"type:text" json:"motivo_anulacao,omitempty"`
+	HoraCaptura    time.Time           `gorm:"not null;index" json:"hora_captura"`
+	ForaDoHorario  bool                `gorm:"default:false" json:"fora_do_horario"` // dentro da tolerância, mas fora da janela
+	Observacoes    string              `gorm:"type:text" json:"observacoes,omitempty"`
+	ContaCota      bool                `gorm:"default:false" json:"conta_cota"`                   // entre os melhores da cota (recalculado a cada validação)
+	Penalidades    []CapturaPenalidade `gorm:"foreignKey:CapturaID" json:"penalidades,omitempty"` // detalhamento do catálogo
 }
 
 // TableName especifica o nome da tabela
@@ -31,15 +65,55 @@ func (Captura) TableName() string {
 	return "capturas"
 }
 
-// CalcularTamanhoFinal aplica penalidade
+// CalcularTamanhoFinal aplica penalidade (sobre a medida do fiscal, se houver)
 func (c *Captura) CalcularTamanhoFinal() float64 {
-	tamanho := c.TamanhoOriginal - c.Penalidade
+	base := c.TamanhoOriginal
+	if c.TamanhoMedido > 0 {
+		base = c.TamanhoMedido
+	}
+
+	if c.PorPeso() {
+		return base // a penalidade é descontada do peso
+	}
+
+	tamanho := base - c.Penalidade
 	if tamanho < 0 {
 		return 0
 	}
 	return tamanho
 }
 
+// CalcularPesoFinal aplica penalidade em g (sobre a pesagem do fiscal, se houver)
+func (c *Captura) CalcularPesoFinal() float64 {
+	base := c.PesoOriginal
+	if c.PesoMedido > 0 {
+		base = c.PesoMedido
+	}
+
+	if !c.PorPeso() {
+		return base
+	}
+
+	peso := base - c.Penalidade
+	if peso < 0 {
+		return 0
+	}
+	return peso
+}
+
+// PorPeso indica se a captura é pontuada pelo peso
+func (c *Captura) PorPeso() bool {
+	return c.Unidade == UnidadePeso
+}
+
+// MedidaPontuada retorna o peso ou o comprimento final, conforme a unidade da captura
+func (c *Captura) MedidaPontuada() float64 {
+	if c.PorPeso() {
+		return c.Peso
+	}
+	return c.Tamanho
+}
+
 // EstaValidada verifica se está validada e não anulada
 func (c *Captura) EstaValidada() bool {
 	return c.Validado && !c.Anulado
@@ -54,18 +128,126 @@ func (c *Captura) Validar(validador string, penalidade float64, motivo string) {
 	c.Penalidade = penalidade
 	c.MotivoPenalidade = motivo
 	c.Tamanho = c.CalcularTamanhoFinal()
+	c.Peso = c.CalcularPesoFinal()
+	c.StatusValidacao = StatusValidacaoValidada
+	c.LiberarReserva()
+}
+
+// RegistrarMedicao guarda a medição de um dos dois fiscais (validação dupla), em cm ou g
+// conforme a unidade da captura
+func (c *Captura) RegistrarMedicao(fiscalID, fiscal string, tamanho, penalidade float64, motivo string) {
+	agora := time.Now()
+
+	if c.Fiscal1ID == nil {
+		c.Fiscal1ID = &fiscalID
+		c.Fiscal1Nome = fiscal
+		c.Fiscal1Tamanho = tamanho
+		c.Fiscal1Penalidade = penalidade
+		c.Fiscal1Em = &agora
+		c.StatusValidacao = StatusValidacaoParcial
+	} else {
+		c.Fiscal2ID = &fiscalID
+		c.Fiscal2Nome = fiscal
+		c.Fiscal2Tamanho = tamanho
+		c.Fiscal2Penalidade = penalidade
+		c.Fiscal2Em = &agora
+	}
+
+	if motivo != "" {
+		if c.MotivoPenalidade != "" {
+			c.MotivoPenalidade += "; "
+		}
+		c.MotivoPenalidade += fiscal + ": " + motivo
+	}
+
+	c.LiberarReserva()
+}
+
+// ConsolidarMedicoes valida com a média das duas medições se a diferença de tamanho
+// e de penalidade couber na tolerância; senão a captura fica divergente
+func (c *Captura) ConsolidarMedicoes(tolerancia float64) bool {
+	if math.Abs(c.Fiscal1Tamanho-c.Fiscal2Tamanho) > tolerancia ||
+		math.Abs(c.Fiscal1Penalidade-c.Fiscal2Penalidade) > tolerancia {
+		c.StatusValidacao = StatusValidacaoDivergente
+		return false
+	}
+
+	if c.PorPeso() {
+		c.PesoMedido = (c.Fiscal1Tamanho + c.Fiscal2Tamanho) / 2
+	} else {
+		c.TamanhoMedido = (c.Fiscal1Tamanho + c.Fiscal2Tamanho) / 2
+	}
+	c.Validar(c.Fiscal1Nome+" / "+c.Fiscal2Nome, (c.Fiscal1Penalidade+c.Fiscal2Penalidade)/2, c.MotivoPenalidade)
+	return true
+}
+
+// MedidaPor verifica se o fiscal já registrou uma das medições
+func (c *Captura) MedidaPor(fiscalID string) bool {
+	return (c.Fiscal1ID != nil && *c.Fiscal1ID == fiscalID) ||
+		(c.Fiscal2ID != nil && *c.Fiscal2ID == fiscalID)
+}
+
+// OcultarMedicoes esconde os valores de cada fiscal enquanto a validação dupla não termina
+func (c *Captura) OcultarMedicoes() {
+	if c.StatusValidacao != StatusValidacaoParcial && c.StatusValidacao != StatusValidacaoDivergente {
+		return
+	}
+
+	c.Fiscal1Tamanho, c.Fiscal1Penalidade = 0, 0
+	c.Fiscal2Tamanho, c.Fiscal2Penalidade = 0, 0
+	c.MotivoPenalidade = ""
+	c.Penalidades = nil // os códigos aplicados por cada fiscal também entregariam a medição
+}
+
+// ConferirRegua compara o número visto no vídeo com a régua sorteada para a inscrição
+func (c *Captura) ConferirRegua(numeroVideo int, regua *Regua) bool {
+	c.NumeroReguaVideo = numeroVideo
+	c.ReguaConfere = regua != nil && regua.Numero == numeroVideo
+	return c.ReguaConfere
 }
 
 // Anular anula a captura
 func (c *Captura) Anular(motivo string) {
 	c.Anulado = true
 	c.MotivoAnulacao = motivo
+	c.LiberarReserva()
+}
+
+// ReservadaPorOutro verifica se outro fiscal tem uma reserva vigente da captura
+func (c *Captura) ReservadaPorOutro(usuarioID string) bool {
+	return c.ReservadaPor != nil && *c.ReservadaPor != usuarioID &&
+		c.ReservadaAte != nil && time.Now().Before(*c.ReservadaAte)
+}
+
+// Reservar entrega a captura ao fiscal pelo tempo informado
+func (c *Captura) Reservar(usuarioID string, duracao time.Duration) {
+	ate := time.Now().Add(duracao)
+	c.ReservadaPor = &usuarioID
+	c.ReservadaAte = &ate
+}
+
+// LiberarReserva devolve a captura à fila
+func (c *Captura) LiberarReserva() {
+	c.ReservadaPor = nil
+	c.ReservadaAte = nil
+}
+
+// AvaliarVideo sinaliza ao fiscal vídeo curto demais ou gravado fora da janela de pesca
+func (c *Captura) AvaliarVideo(etapa *Etapa) {
+	c.VideoCurto = c.DuracaoVideo > 0 && c.DuracaoVideo < DuracaoMinimaVideo
+	c.GravadoForaEtapa = false
+
+	if c.DataGravacao != nil && etapa != nil {
+		tolerado, err := etapa.VerificarHorario(*c.DataGravacao)
+		c.GravadoForaEtapa = tolerado || err != nil
+	}
 }
 
-// AtingeTamanhoMinimo verifica se atinge tamanho mínimo
-func (c *Captura) AtingeTamanhoMinimo() bool {
-	if c.Especie == EspecieTraira {
-		return true // traíra não tem mínimo
+// AtingeTamanhoMinimo verifica se atinge o tamanho mínimo da regra da espécie.
+// Peixe só pesado, sem medida de comprimento, não tem como ser conferido.
+func (c *Captura) AtingeTamanhoMinimo(regra RegraEspecie) bool {
+	if c.PorPeso() && c.TamanhoOriginal == 0 && c.TamanhoMedido == 0 {
+		return true
 	}
-	return c.Tamanho >= TamanhoMinimoTucunare
+	return c.Tamanho >= regra.TamanhoMinimo
 }
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoriaCompetidor é uma categoria da edição definida por faixa de idade e sexo
+// (infantil, feminino, master...). O competidor entra em todas em que se encaixa.
+type CategoriaCompetidor struct {
+	BaseModel
+	EdicaoID    uuid.UUID `gorm:"type:uuid;not null;index:idx_categoria_edicao_codigo" json:"edicao_id" binding:"required"`
+	Edicao      *Edicao   `gorm:"foreignKey:EdicaoID" json:"edicao,omitempty"`
+	Codigo      string    `gorm:"size:30;not null;index:idx_categoria_edicao_codigo" json:"codigo" binding:"required"` // usado como categoria do ranking
+	Nome        string    `gorm:"size:100;not null" json:"nome" binding:"required"`
+	IdadeMinima int       `gorm:"default:0" json:"idade_minima" binding:"min=0"` // na data da etapa; 0 = sem mínimo
+	IdadeMaxima int       `gorm:"default:0" json:"idade_maxima" binding:"min=0"` // na data da etapa; 0 = sem máximo
+	Sexo        string    `gorm:"size:1" json:"sexo"`                            // M, F ou vazio para ambos
+	Ativa       bool      `gorm:"default:true" json:"ativa"`
+	Ordem       int       `gorm:"default:0" json:"ordem"` // para ordenação na exibição
+}
+
+// TableName especifica o nome da tabela
+func (CategoriaCompetidor) TableName() string {
+	return "categorias_competidor"
+}
+
+// Aceita verifica se o competidor se encaixa na categoria na data informada
+func (cat *CategoriaCompetidor) Aceita(competidor *Competidor, data time.Time) bool {
+	if !cat.Ativa {
+		return false
+	}
+
+	if cat.Sexo != "" && competidor.Sexo != cat.Sexo {
+		return false
+	}
+
+	if cat.IdadeMinima > 0 || cat.IdadeMaxima > 0 {
+		if competidor.DataNascimento == nil {
+			return false
+		}
+		idade := competidor.IdadeEm(data)
+		if cat.IdadeMinima > 0 && idade < cat.IdadeMinima {
+			return false
+		}
+		if cat.IdadeMaxima > 0 && idade > cat.IdadeMaxima {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EnquadrarCategorias retorna as categorias da edição em que o competidor se encaixa na data da etapa
+func EnquadrarCategorias(categorias []CategoriaCompetidor, competidor *Competidor, data time.Time) []CategoriaCompetidor {
+	var enquadradas []CategoriaCompetidor
+	for _, categoria := range categorias {
+		if categoria.Aceita(competidor, data) {
+			enquadradas = append(enquadradas, categoria)
+		}
+	}
+	return enquadradas
+}
+
+// TemCategoria verifica se a inscrição foi enquadrada na categoria
+func (i *Inscricao) TemCategoria(codigo string) bool {
+	for _, categoria := range i.Categorias {
+		if categoria.Codigo == codigo {
+			return true
+		}
+	}
+	return false
+}
@@ -16,11 +16,13 @@ type Competidor struct {
 	Telefone        string     `gorm:"size:20" json:"telefone" binding:"required"`
 	CPF             string     `gorm:"size:14;uniqueIndex" json:"cpf" binding:"required"`
 	DataNascimento  *time.Time `json:"data_nascimento" binding:"required"`
+	Sexo            string     `gorm:"size:1" json:"sexo" binding:"omitempty,oneof=M F"` // usado nas categorias
 	Cidade          string     `gorm:"size:100" json:"cidade" binding:"required"`
 	Estado          string     `gorm:"size:2" json:"estado" binding:"required"`
 	LicencaPesca    string     `gorm:"size:50" json:"licenca_pesca"`
 	ValidadeLicenca *time.Time `json:"validade_licenca"`
 	FotoURL         string     `gorm:"size:500" json:"foto_url"`
+	FotoArquivo     string     `gorm:"size:500" json:"-"` // chave da foto no storage
 	Ativo           bool       `gorm:"default:true" json:"ativo"`
 	Banido          bool       `gorm:"default:false" json:"banido"`
 	MotivoBANimento string     `gorm:"type:text" json:"motivo_banimento,omitempty"`
@@ -84,26 +86,25 @@ func (c *Competidor) LicencaValida() bool {
 
 // Idade calcula a idade do competidor
 func (c *Competidor) Idade() int {
+	return c.IdadeEm(time.Now())
+}
+
+// IdadeEm calcula a idade do competidor em uma data (ex.: a da etapa)
+func (c *Competidor) IdadeEm(data time.Time) int {
 	if c.DataNascimento == nil {
 		return 0
 	}
 
-	now := time.Now()
-	age := now.Year() - c.DataNascimento.Year()
+	age := data.Year() - c.DataNascimento.Year()
 
-	if now.Month() < c.DataNascimento.Month() ||
-		(now.Month() == c.DataNascimento.Month() && now.Day() < c.DataNascimento.Day()) {
+	if data.Month() < c.DataNascimento.Month() ||
+		(data.Month() == c.DataNascimento.Month() && data.Day() < c.DataNascimento.Day()) {
 		age--
 	}
 
 	return age
 }
 
-// EhInfantil verifica se é categoria infantil (até 16 anos)
-func (c *Competidor) EhInfantil() bool {
-	return c.Idade() <= 16
-}
-
 // Banir bane o competidor do torneio
 func (c *Competidor) Banir(motivo string) {
 	c.Banido = true
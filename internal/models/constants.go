@@ -40,6 +40,31 @@ func GetStatusEtapa() []string {
 	}
 }
 
+// ============================================
+// STATUS DE VALIDAÇÃO DA CAPTURA
+// ============================================
+
+const (
+	StatusValidacaoPendente   = "pendente"
+	StatusValidacaoParcial    = "parcial"    // validação dupla: falta a segunda medição
+	StatusValidacaoDivergente = "divergente" // medições fora da tolerância, decide o organizador
+	StatusValidacaoValidada   = "validada"
+)
+
+// ============================================
+// RECURSOS
+// ============================================
+
+const (
+	StatusRecursoAberto     = "aberto"
+	StatusRecursoEmAnalise  = "em_analise"
+	StatusRecursoDeferido   = "deferido"
+	StatusRecursoIndeferido = "indeferido"
+
+	TipoRecursoAnulacao   = "anulacao"
+	TipoRecursoPenalidade = "penalidade"
+)
+
 // ============================================
 // STATUS DE PAGAMENTO
 // ============================================
@@ -65,49 +90,152 @@ func GetStatusPagamento() []string {
 // ESPÉCIES DE PEIXE
 // ============================================
 
+// Códigos das espécies do catálogo padrão (ver EspeciesPadrao)
 const (
 	EspecieTucunareAzul    = "tucunare_azul"
 	EspecieTucunareAmarelo = "tucunare_amarelo"
 	EspecieTraira          = "traira"
 )
 
-// GetEspecies retorna todas as espécies válidas
-func GetEspecies() []string {
+// ============================================
+// CATEGORIAS DE RANKING
+// ============================================
+
+const (
+	CategoriaGeral        = "geral"
+	CategoriaMaiorAzul    = "maior_azul"
+	CategoriaMaiorAmarelo = "maior_amarelo"
+	CategoriaMaiorTraira  = "maior_traira"
+)
+
+// Sexo do competidor, usado nas categorias
+const (
+	SexoMasculino = "M"
+	SexoFeminino  = "F"
+)
+
+// ============================================
+// CRITÉRIOS DE DESEMPATE
+// ============================================
+
+const (
+	DesempateMaiorPeixe       = "maior_peixe"
+	DesempateQuantidadePeixes = "quantidade_peixes"
+	DesempateUltimaCaptura    = "ultima_captura" // última captura que contou feita mais cedo
+)
+
+// GetCriteriosDesempate retorna todos os critérios de desempate
+func GetCriteriosDesempate() []string {
 	return []string{
-		EspecieTucunareAzul,
-		EspecieTucunareAmarelo,
-		EspecieTraira,
+		DesempateMaiorPeixe,
+		DesempateQuantidadePeixes,
+		DesempateUltimaCaptura,
 	}
 }
 
-// GetNomeEspecie retorna o nome amigável da espécie
-func GetNomeEspecie(especie string) string {
-	nomes := map[string]string{
-		EspecieTucunareAzul:    "Tucunaré Azul",
-		EspecieTucunareAmarelo: "Tucunaré Amarelo",
-		EspecieTraira:          "Traíra",
+// DesempatePadrao é a ordem de desempate do regulamento
+func DesempatePadrao() []string {
+	return []string{
+		DesempateMaiorPeixe,
+		DesempateQuantidadePeixes,
+		DesempateUltimaCaptura,
 	}
-	return nomes[especie]
+}
+
+// Critérios de desempate da classificação da temporada (edição)
+const (
+	DesempateVitorias        = "vitorias"         // mais etapas vencidas
+	DesempatePontosBrutos    = "pontos_brutos"    // soma sem os descartes
+	DesempatePontuacaoEtapas = "pontuacao_etapas" // soma das pontuações obtidas nas etapas
+	DesempateUltimaEtapa     = "ultima_etapa"     // melhor colocação na etapa mais recente
+)
+
+// GetCriteriosDesempateTemporada retorna todos os critérios de desempate da temporada
+func GetCriteriosDesempateTemporada() []string {
+	return []string{
+		DesempateVitorias,
+		DesempatePontosBrutos,
+		DesempatePontuacaoEtapas,
+		DesempateUltimaEtapa,
+	}
+}
+
+// DesempateTemporadaPadrao é a ordem de desempate da temporada quando a edição não define
+func DesempateTemporadaPadrao() []string {
+	return []string{
+		DesempateVitorias,
+		DesempatePontosBrutos,
+		DesempateUltimaEtapa,
+	}
+}
+
+// TabelaPontosPadrao são os pontos por colocação nas etapas quando a edição não define
+func TabelaPontosPadrao() []float64 {
+	return []float64{25, 20, 16, 13, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
 }
 
 // ============================================
-// CATEGORIAS DE RANKING
+// MODOS DE MEDIÇÃO
 // ============================================
 
 const (
-	CategoriaGeral        = "geral"
-	CategoriaMaiorAzul    = "maior_azul"
-	CategoriaMaiorAmarelo = "maior_amarelo"
-	CategoriaMaiorTraira  = "maior_traira"
+	MedicaoComprimento = "comprimento"
+	MedicaoPeso        = "peso"
+	MedicaoAmbos       = "ambos" // mede os dois; pontua pelo comprimento
 )
 
-// GetCategoriasRanking retorna todas as categorias de ranking
-func GetCategoriasRanking() []string {
+// GetModosMedicao retorna todos os modos de medição das etapas
+func GetModosMedicao() []string {
 	return []string{
-		CategoriaGeral,
-		CategoriaMaiorAzul,
-		CategoriaMaiorAmarelo,
-		CategoriaMaiorTraira,
+		MedicaoComprimento,
+		MedicaoPeso,
+		MedicaoAmbos,
+	}
+}
+
+// Unidades da medida pontuada e das penalidades
+const (
+	UnidadeComprimento = "cm"
+	UnidadePeso        = "g"
+)
+
+// ============================================
+// ESTRATÉGIAS DE PONTUAÇÃO
+// ============================================
+
+const (
+	EstrategiaSomaComprimentos = "soma_comprimentos"
+	EstrategiaPontosPorPeixe   = "pontos_por_peixe"
+	EstrategiaMaiorPeixe       = "maior_peixe"
+)
+
+// GetEstrategiasPontuacao retorna todas as estratégias de pontuação
+func GetEstrategiasPontuacao() []string {
+	return []string{
+		EstrategiaSomaComprimentos,
+		EstrategiaPontosPorPeixe,
+		EstrategiaMaiorPeixe,
+	}
+}
+
+// ============================================
+// FORMAS DE PAGAMENTO DA PREMIAÇÃO
+// ============================================
+
+const (
+	FormaPagamentoPix           = "pix"
+	FormaPagamentoTransferencia = "transferencia"
+	FormaPagamentoDinheiro      = "dinheiro"
+	FormaPagamentoCheque        = "cheque"
+)
+
+// GetFormasPagamento retorna todas as formas de pagamento de prêmios
+func GetFormasPagamento() []string {
+	return []string{
+		FormaPagamentoPix,
+		FormaPagamentoTransferencia,
+		FormaPagamentoDinheiro,
+		FormaPagamentoCheque,
 	}
 }
 
@@ -126,8 +254,22 @@ const (
 	PenalidadeMinima = 0.5
 	PenalidadeMaxima = 3.0
 
+	// Penalidades nas etapas por peso (em g)
+	PenalidadeMinimaPeso = 10.0
+	PenalidadeMaximaPeso = 500.0
+
 	// Horários
 	HorarioLimiteRetorno = "16:00"
+	FusoPadrao           = "America/Sao_Paulo"
+
+	// Duração mínima do vídeo da captura (em segundos)
+	DuracaoMinimaVideo = 10
+
+	// Prazo padrão para abrir recurso após o fim da etapa (em horas)
+	PrazoRecursoHoras = 24
+
+	// Tempo que uma captura fica reservada ao fiscal que a pegou na fila (em minutos)
+	DuracaoReservaMinutos = 10
 )
 
 // ============================================
@@ -182,6 +324,7 @@ const (
 	ErrCotaExcedida  = "cota de peixes excedida"
 	ErrVideoInvalido = "vídeo inválido ou não encontrado"
 	ErrReguaInvalida = "número de régua inválido"
+	ErrForaDoHorario = "captura fora do horário da etapa"
 
 	ErrDadosInvalidos   = "dados inválidos"
 	ErrCampoObrigatorio = "campo obrigatório não informado"
@@ -203,17 +346,6 @@ const (
 // VALIDAÇÕES
 // ============================================
 
-// ValidarEspecie valida se a espécie é válida
-func ValidarEspecie(especie string) bool {
-	especies := GetEspecies()
-	for _, e := range especies {
-		if e == especie {
-			return true
-		}
-	}
-	return false
-}
-
 // ValidarStatusEtapa valida se o status da etapa é válido
 func ValidarStatusEtapa(status string) bool {
 	statusValidos := GetStatusEtapa()
@@ -247,7 +379,63 @@ func ValidarTipoUsuario(tipo string) bool {
 	return false
 }
 
-// ValidarPenalidade valida se a penalidade está dentro dos limites
-func ValidarPenalidade(penalidade float64) bool {
-	return penalidade >= 0 && penalidade <= PenalidadeMaxima
+// ValidarPenalidade valida se a penalidade está dentro dos limites da unidade
+func ValidarPenalidade(penalidade float64, unidade string) bool {
+	return penalidade >= 0 && penalidade <= PenalidadeMaximaEm(unidade)
+}
+
+// PenalidadeMinimaEm retorna o menor valor de penalidade do catálogo na unidade
+func PenalidadeMinimaEm(unidade string) float64 {
+	if unidade == UnidadePeso {
+		return PenalidadeMinimaPeso
+	}
+	return PenalidadeMinima
+}
+
+// PenalidadeMaximaEm retorna o limite de penalidade por captura na unidade
+func PenalidadeMaximaEm(unidade string) float64 {
+	if unidade == UnidadePeso {
+		return PenalidadeMaximaPeso
+	}
+	return PenalidadeMaxima
+}
+
+// ValidarCriterioDesempate valida se o critério de desempate é válido
+func ValidarCriterioDesempate(criterio string) bool {
+	for _, c := range GetCriteriosDesempate() {
+		if c == criterio {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidarCriterioDesempateTemporada valida se o critério de desempate da temporada é válido
+func ValidarCriterioDesempateTemporada(criterio string) bool {
+	for _, c := range GetCriteriosDesempateTemporada() {
+		if c == criterio {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidarModoMedicao valida se o modo de medição da etapa é válido
+func ValidarModoMedicao(medicao string) bool {
+	for _, m := range GetModosMedicao() {
+		if m == medicao {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidarFormaPagamento valida se a forma de pagamento do prêmio é válida
+func ValidarFormaPagamento(forma string) bool {
+	for _, f := range GetFormasPagamento() {
+		if f == forma {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Classificado reúne o que é comparado para ordenar um competidor na etapa
+type Classificado struct {
+	InscricaoID      string
+	PontuacaoTotal   float64
+	MaiorPeixe       float64
+	QuantidadePeixes int
+	UltimaCaptura    time.Time // hora da última captura que contou; zero se não pontuou
+
+	// Preenchidos por Classificar
+	Posicao           int
+	CriterioDesempate string // critério que o separou do anterior com a mesma pontuação
+	Empatado          bool   // divide a posição com outro competidor
+}
+
+// UltimaCapturaContada retorna a hora da última captura da inscrição que contou na cota
+func (i *Inscricao) UltimaCapturaContada() time.Time {
+	var ultima time.Time
+	for _, captura := range i.Capturas {
+		if captura.ContaCota && captura.HoraCaptura.After(ultima) {
+			ultima = captura.HoraCaptura
+		}
+	}
+	return ultima
+}
+
+// CriteriosDesempate retorna a lista de desempate da etapa, ou a do regulamento
+func (e *Etapa) CriteriosDesempate() []string {
+	if strings.TrimSpace(e.Desempate) == "" {
+		return DesempatePadrao()
+	}
+
+	var criterios []string
+	for _, criterio := range strings.Split(e.Desempate, ",") {
+		if criterio = strings.TrimSpace(criterio); criterio != "" {
+			criterios = append(criterios, criterio)
+		}
+	}
+	return criterios
+}
+
+// ValidarDesempate confere se todos os critérios da lista existem e não se repetem
+func ValidarDesempate(desempate string) error {
+	vistos := map[string]bool{}
+	for _, criterio := range strings.Split(desempate, ",") {
+		criterio = strings.TrimSpace(criterio)
+		if criterio == "" {
+			continue
+		}
+		if !ValidarCriterioDesempate(criterio) {
+			return fmt.Errorf("critério de desempate inválido: %s", criterio)
+		}
+		if vistos[criterio] {
+			return fmt.Errorf("critério de desempate repetido: %s", criterio)
+		}
+		vistos[criterio] = true
+	}
+	return nil
+}
+
+// Classificar ordena pela pontuação e aplica os critérios de desempate em ordem.
+// Quem continuar empatado em todos os critérios divide a posição (1, 1, 3...).
+func Classificar(classificados []Classificado, criterios []string) {
+	sort.SliceStable(classificados, func(a, b int) bool {
+		if classificados[a].PontuacaoTotal != classificados[b].PontuacaoTotal {
+			return classificados[a].PontuacaoTotal > classificados[b].PontuacaoTotal
+		}
+		_, aVence := desempatar(&classificados[a], &classificados[b], criterios)
+		return aVence
+	})
+
+	for i := range classificados {
+		atual := &classificados[i]
+		atual.Posicao = i + 1
+		atual.CriterioDesempate = ""
+		atual.Empatado = false
+
+		if i == 0 {
+			continue
+		}
+
+		anterior := &classificados[i-1]
+		if anterior.PontuacaoTotal != atual.PontuacaoTotal {
+			continue
+		}
+
+		criterio, _ := desempatar(anterior, atual, criterios)
+		if criterio == "" {
+			atual.Posicao = anterior.Posicao
+			atual.Empatado = true
+			anterior.Empatado = true
+			continue
+		}
+		atual.CriterioDesempate = criterio
+	}
+}
+
+// desempatar retorna o primeiro critério que diferencia a de b e se a fica à frente.
+// Critério vazio significa empate em todos.
+func desempatar(a, b *Classificado, criterios []string) (string, bool) {
+	for _, criterio := range criterios {
+		switch criterio {
+		case DesempateMaiorPeixe:
+			if a.MaiorPeixe != b.MaiorPeixe {
+				return criterio, a.MaiorPeixe > b.MaiorPeixe
+			}
+		case DesempateQuantidadePeixes:
+			if a.QuantidadePeixes != b.QuantidadePeixes {
+				return criterio, a.QuantidadePeixes > b.QuantidadePeixes
+			}
+		case DesempateUltimaCaptura:
+			if !a.UltimaCaptura.Equal(b.UltimaCaptura) {
+				// Quem completou antes fica à frente; sem captura fica atrás
+				if a.UltimaCaptura.IsZero() || b.UltimaCaptura.IsZero() {
+					return criterio, b.UltimaCaptura.IsZero()
+				}
+				return criterio, a.UltimaCaptura.Before(b.UltimaCaptura)
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassificar(t *testing.T) {
+	cedo := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tarde := cedo.Add(2 * time.Hour)
+
+	casos := []struct {
+		nome       string
+		criterios  []string
+		entrada    []Classificado
+		ordem      []string
+		posicoes   []int
+		empatados  []bool
+		criterioDe []string
+	}{
+		{
+			nome:      "pontuações diferentes",
+			criterios: DesempatePadrao(),
+			entrada: []Classificado{
+				{InscricaoID: "b", PontuacaoTotal: 80},
+				{InscricaoID: "a", PontuacaoTotal: 95},
+				{InscricaoID: "c", PontuacaoTotal: 60},
+			},
+			ordem:      []string{"a", "b", "c"},
+			posicoes:   []int{1, 2, 3},
+			empatados:  []bool{false, false, false},
+			criterioDe: []string{"", "", ""},
+		},
+		{
+			nome:      "maior peixe desempata",
+			criterios: DesempatePadrao(),
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 90, MaiorPeixe: 40},
+				{InscricaoID: "b", PontuacaoTotal: 90, MaiorPeixe: 45},
+			},
+			ordem:      []string{"b", "a"},
+			posicoes:   []int{1, 2},
+			empatados:  []bool{false, false},
+			criterioDe: []string{"", DesempateMaiorPeixe},
+		},
+		{
+			nome:      "quantidade desempata quando o maior peixe empata",
+			criterios: DesempatePadrao(),
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 3},
+				{InscricaoID: "b", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 4},
+			},
+			ordem:      []string{"b", "a"},
+			posicoes:   []int{1, 2},
+			empatados:  []bool{false, false},
+			criterioDe: []string{"", DesempateQuantidadePeixes},
+		},
+		{
+			nome:      "quem completou antes fica à frente",
+			criterios: DesempatePadrao(),
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 4, UltimaCaptura: tarde},
+				{InscricaoID: "b", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 4, UltimaCaptura: cedo},
+			},
+			ordem:      []string{"b", "a"},
+			posicoes:   []int{1, 2},
+			empatados:  []bool{false, false},
+			criterioDe: []string{"", DesempateUltimaCaptura},
+		},
+		{
+			nome:      "empate em todos os critérios divide a posição",
+			criterios: DesempatePadrao(),
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 4, UltimaCaptura: cedo},
+				{InscricaoID: "b", PontuacaoTotal: 90, MaiorPeixe: 40, QuantidadePeixes: 4, UltimaCaptura: cedo},
+				{InscricaoID: "c", PontuacaoTotal: 70},
+			},
+			ordem:      []string{"a", "b", "c"},
+			posicoes:   []int{1, 1, 3},
+			empatados:  []bool{true, true, false},
+			criterioDe: []string{"", "", ""},
+		},
+		{
+			nome:      "sem critérios, mesma pontuação empata",
+			criterios: nil,
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 50},
+				{InscricaoID: "b", PontuacaoTotal: 80, MaiorPeixe: 10},
+				{InscricaoID: "c", PontuacaoTotal: 80, MaiorPeixe: 30},
+			},
+			ordem:      []string{"b", "c", "a"},
+			posicoes:   []int{1, 1, 3},
+			empatados:  []bool{true, true, false},
+			criterioDe: []string{"", "", ""},
+		},
+		{
+			nome:      "sem captura fica atrás na última captura",
+			criterios: []string{DesempateUltimaCaptura},
+			entrada: []Classificado{
+				{InscricaoID: "a", PontuacaoTotal: 0},
+				{InscricaoID: "b", PontuacaoTotal: 0, UltimaCaptura: tarde},
+			},
+			ordem:      []string{"b", "a"},
+			posicoes:   []int{1, 2},
+			empatados:  []bool{false, false},
+			criterioDe: []string{"", DesempateUltimaCaptura},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			Classificar(caso.entrada, caso.criterios)
+
+			for i, cl := range caso.entrada {
+				if cl.InscricaoID != caso.ordem[i] {
+					t.Fatalf("posição %d: esperado %s, obtido %s", i, caso.ordem[i], cl.InscricaoID)
+				}
+				if cl.Posicao != caso.posicoes[i] {
+					t.Errorf("%s: posição esperada %d, obtida %d", cl.InscricaoID, caso.posicoes[i], cl.Posicao)
+				}
+				if cl.Empatado != caso.empatados[i] {
+					t.Errorf("%s: empatado esperado %v, obtido %v", cl.InscricaoID, caso.empatados[i], cl.Empatado)
+				}
+				if cl.CriterioDesempate != caso.criterioDe[i] {
+					t.Errorf("%s: critério esperado %q, obtido %q", cl.InscricaoID, caso.criterioDe[i], cl.CriterioDesempate)
+				}
+			}
+		})
+	}
+}
@@ -2,13 +2,20 @@ package models
 
 type Edicao struct {
 	BaseModel
-	Ano         int          `gorm:"not null;uniqueIndex" json:"ano" binding:"required"`
-	Nome        string       `gorm:"size:100;not null" json:"nome" binding:"required"`
-	Descricao   string       `gorm:"type:text" json:"descricao"`
-	ImagemURL   string       `gorm:"size:500" json:"imagem_url"`
-	Ativa       bool         `gorm:"default:true" json:"ativa"`
-	Etapas      []Etapa      `gorm:"foreignKey:EdicaoID" json:"etapas,omitempty"`
-	Modalidades []Modalidade `gorm:"many2many:edicao_modalidades;" json:"modalidades,omitempty"`
+	Ano            int          `gorm:"not null;uniqueIndex" json:"ano" binding:"required"`
+	Nome           string       `gorm:"size:100;not null" json:"nome" binding:"required"`
+	Descricao      string       `gorm:"type:text" json:"descricao"`
+	ImagemURL      string       `gorm:"size:500" json:"imagem_url"`
+	ImagemArquivo  string       `gorm:"size:500" json:"-"` // chave da imagem no storage
+	Ativa          bool         `gorm:"default:true" json:"ativa"`
+	Patrocinadores string       `gorm:"size:500" json:"patrocinadores"` // rodapé dos relatórios impressos
+	Etapas         []Etapa      `gorm:"foreignKey:EdicaoID" json:"etapas,omitempty"`
+	Modalidades    []Modalidade `gorm:"many2many:edicao_modalidades;" json:"modalidades,omitempty"`
+
+	// Classificação da temporada
+	TabelaPontos       string `gorm:"size:500" json:"tabela_pontos"`       // pontos por colocação, separados por vírgula; vazio = padrão
+	DescartarPiores    int    `gorm:"default:0" json:"descartar_piores"`   // piores resultados descartados de cada competidor
+	DesempateTemporada string `gorm:"size:200" json:"desempate_temporada"` // critérios em ordem, separados por vírgula
 }
 
 func (Edicao) TableName() string {
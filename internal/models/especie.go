@@ -0,0 +1,102 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Especie é uma espécie de peixe do catálogo de uma edição
+type Especie struct {
+	BaseModel
+	EdicaoID       uuid.UUID `gorm:"type:uuid;not null;index:idx_especie_edicao_codigo" json:"edicao_id" binding:"required"`
+	Edicao         *Edicao   `gorm:"foreignKey:EdicaoID" json:"edicao,omitempty"`
+	Codigo         string    `gorm:"size:30;not null;index:idx_especie_edicao_codigo" json:"codigo" binding:"required"`
+	NomeComum      string    `gorm:"size:100;not null" json:"nome_comum" binding:"required"`
+	NomeCientifico string    `gorm:"size:150" json:"nome_cientifico"`
+	IconeURL       string    `gorm:"size:500" json:"icone_url"`
+	Ativa          bool      `gorm:"default:true" json:"ativa"`
+	Ordem          int       `gorm:"default:0" json:"ordem"` // para ordenação na exibição
+}
+
+// TableName especifica o nome da tabela
+func (Especie) TableName() string {
+	return "especies"
+}
+
+// TamanhoMaximoCodigoEspecie garante que "maior_" + código caiba em Ranking.Categoria (30)
+const TamanhoMaximoCodigoEspecie = 24
+
+// CategoriaMaiorPeixe retorna a categoria de ranking do maior peixe da espécie.
+// As espécies do regulamento original mantêm as categorias já publicadas.
+func (e *Especie) CategoriaMaiorPeixe() string {
+	legadas := map[string]string{
+		EspecieTucunareAzul:    CategoriaMaiorAzul,
+		EspecieTucunareAmarelo: CategoriaMaiorAmarelo,
+		EspecieTraira:          CategoriaMaiorTraira,
+	}
+	if categoria, ok := legadas[e.Codigo]; ok {
+		return categoria
+	}
+	return "maior_" + e.Codigo
+}
+
+// EspeciesPadrao é o catálogo do regulamento, usado quando a edição não cadastrou espécies
+// e gravado na edição junto com a primeira espécie própria
+func EspeciesPadrao() CatalogoEspecies {
+	return CatalogoEspecies{
+		{Codigo: EspecieTucunareAzul, NomeComum: "Tucunaré Azul", NomeCientifico: "Cichla piquiti", Ativa: true, Ordem: 1},
+		{Codigo: EspecieTucunareAmarelo, NomeComum: "Tucunaré Amarelo", NomeCientifico: "Cichla kelberi", Ativa: true, Ordem: 2},
+		{Codigo: EspecieTraira, NomeComum: "Traíra", NomeCientifico: "Hoplias malabaricus", Ativa: true, Ordem: 3},
+	}
+}
+
+// CatalogoEspecies são as espécies de uma edição
+type CatalogoEspecies []Especie
+
+// Ativas retorna as espécies que podem ser registradas na edição
+func (c CatalogoEspecies) Ativas() CatalogoEspecies {
+	var ativas CatalogoEspecies
+	for _, especie := range c {
+		if especie.Ativa {
+			ativas = append(ativas, especie)
+		}
+	}
+	return ativas
+}
+
+// Codigos retorna os códigos das espécies do catálogo
+func (c CatalogoEspecies) Codigos() []string {
+	codigos := make([]string, 0, len(c))
+	for _, especie := range c {
+		codigos = append(codigos, especie.Codigo)
+	}
+	return codigos
+}
+
+// ValidarEspecie verifica se a espécie está ativa no catálogo
+func (c CatalogoEspecies) ValidarEspecie(codigo string) bool {
+	for _, especie := range c.Ativas() {
+		if especie.Codigo == codigo {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNomeEspecie retorna o nome amigável da espécie, mesmo que desativada
+func (c CatalogoEspecies) GetNomeEspecie(codigo string) string {
+	for _, especie := range c {
+		if especie.Codigo == codigo {
+			return especie.NomeComum
+		}
+	}
+	return codigo
+}
+
+// GetCategoriasRanking retorna a categoria geral e a de maior peixe de cada espécie ativa
+func (c CatalogoEspecies) GetCategoriasRanking() []string {
+	categorias := []string{CategoriaGeral}
+	for _, especie := range c.Ativas() {
+		categorias = append(categorias, especie.CategoriaMaiorPeixe())
+	}
+	return categorias
+}
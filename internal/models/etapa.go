@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,13 @@ type Etapa struct {
 	DataLargada       time.Time   `gorm:"not null" json:"data_largada" binding:"required"`
 	HoraLargada       string      `gorm:"size:10" json:"hora_largada"` // "07:00"
 	HoraRetorno       string      `gorm:"size:10;default:'16:00'" json:"hora_retorno"`
+	Fuso              string      `gorm:"size:50;default:'America/Sao_Paulo'" json:"fuso"`        // fuso IANA do local da etapa
+	ToleranciaMinutos int         `gorm:"default:0" json:"tolerancia_minutos"`                    // capturas até N min fora da janela são sinalizadas em vez de recusadas
+	Medicao           string      `gorm:"size:20;default:'comprimento'" json:"medicao"`           // comprimento, peso ou ambos
+	DuplaValidacao    bool        `gorm:"default:false" json:"dupla_validacao"`                   // exige medições independentes de dois fiscais
+	ToleranciaMedicao float64     `gorm:"type:decimal(10,2);default:0" json:"tolerancia_medicao"` // diferença máxima (cm ou g) entre as duas medições
+	PrazoRecursoHoras int         `gorm:"default:24" json:"prazo_recurso_horas"`                  // prazo para recursos após o retorno
+	Desempate         string      `gorm:"size:200" json:"desempate"`                              // critérios em ordem, separados por vírgula; vazio = regulamento
 	ValorInscricao    float64     `gorm:"type:decimal(10,2)" json:"valor_inscricao"`
 	VagasDisponiveis  int         `json:"vagas_disponiveis"`
 	VagasOcupadas     int         `gorm:"default:0" json:"vagas_ocupadas"`
@@ -29,6 +37,10 @@ type Etapa struct {
 	ImagemURL         string      `gorm:"size:500" json:"imagem_url"`
 	Inscricoes        []Inscricao `gorm:"foreignKey:EtapaID" json:"inscricoes,omitempty"`
 	Reguas            []Regua     `gorm:"foreignKey:EtapaID" json:"reguas,omitempty"`
+
+	// Pontuação própria da etapa; vazia usa a da modalidade
+	EstrategiaPontuacao string  `gorm:"size:30" json:"estrategia_pontuacao,omitempty"`
+	PontosPorPeixe      float64 `gorm:"type:decimal(10,2);default:0" json:"pontos_por_peixe,omitempty"`
 }
 
 // PodeInscrever verifica se pode fazer inscrições
@@ -75,6 +87,109 @@ func (e *Etapa) DecrementarVagas() {
 	}
 }
 
+// Localizacao retorna o fuso da etapa (padrão America/Sao_Paulo)
+func (e *Etapa) Localizacao() (*time.Location, error) {
+	fuso := e.Fuso
+	if fuso == "" {
+		fuso = FusoPadrao
+	}
+	return time.LoadLocation(fuso)
+}
+
+// JanelaPesca retorna o início (largada) e o fim (retorno) da pesca no fuso da etapa.
+// O dia é o de DataLargada como foi gravado (sem conversão de fuso, que levaria a data
+// gravada à meia-noite UTC para o dia anterior); sem HoraLargada a janela começa à meia-noite.
+func (e *Etapa) JanelaPesca() (time.Time, time.Time, error) {
+	loc, err := e.Localizacao()
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("fuso inválido: %s", e.Fuso)
+	}
+
+	dia := e.DataLargada
+
+	largada := "00:00"
+	if e.HoraLargada != "" {
+		largada = e.HoraLargada
+	}
+	retorno := HorarioLimiteRetorno
+	if e.HoraRetorno != "" {
+		retorno = e.HoraRetorno
+	}
+
+	hl, err := time.Parse("15:04", largada)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("hora de largada inválida: %s", largada)
+	}
+	hr, err := time.Parse("15:04", retorno)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("hora de retorno inválida: %s", retorno)
+	}
+
+	inicio := time.Date(dia.Year(), dia.Month(), dia.Day(), hl.Hour(), hl.Minute(), 0, 0, loc)
+	fim := time.Date(dia.Year(), dia.Month(), dia.Day(), hr.Hour(), hr.Minute(), 0, 0, loc)
+
+	// Retorno antes da largada: pesca noturna, termina no dia seguinte
+	if !fim.After(inicio) {
+		fim = fim.AddDate(0, 0, 1)
+	}
+
+	return inicio, fim, nil
+}
+
+// VerificarHorario confere se o horário está na janela de pesca.
+// Fora da janela, mas dentro da tolerância, retorna tolerado = true (captura sinalizada);
+// além da tolerância retorna erro (captura recusada).
+func (e *Etapa) VerificarHorario(hora time.Time) (tolerado bool, err error) {
+	inicio, fim, err := e.JanelaPesca()
+	if err != nil {
+		return false, err
+	}
+
+	if !hora.Before(inicio) && !hora.After(fim) {
+		return false, nil
+	}
+
+	tolerancia := time.Duration(e.ToleranciaMinutos) * time.Minute
+	if !hora.Before(inicio.Add(-tolerancia)) && !hora.After(fim.Add(tolerancia)) {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("%s (%s às %s)", ErrForaDoHorario, inicio.Format("02/01/2006 15:04"), fim.Format("15:04"))
+}
+
+// PrazoRecurso retorna até quando o competidor pode abrir recurso (fim da janela de pesca + prazo)
+func (e *Etapa) PrazoRecurso() (time.Time, error) {
+	_, fim, err := e.JanelaPesca()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	horas := e.PrazoRecursoHoras
+	if horas <= 0 {
+		horas = PrazoRecursoHoras
+	}
+
+	return fim.Add(time.Duration(horas) * time.Hour), nil
+}
+
+// MedeComprimento indica se as capturas da etapa precisam da medida em cm
+func (e *Etapa) MedeComprimento() bool {
+	return e.Medicao != MedicaoPeso
+}
+
+// MedePeso indica se as capturas da etapa precisam do peso em g
+func (e *Etapa) MedePeso() bool {
+	return e.Medicao == MedicaoPeso || e.Medicao == MedicaoAmbos
+}
+
+// Unidade retorna a unidade da medida pontuada e das penalidades da etapa
+func (e *Etapa) Unidade() string {
+	if e.Medicao == MedicaoPeso {
+		return UnidadePeso
+	}
+	return UnidadeComprimento
+}
+
 func (Etapa) TableName() string {
 	return "etapas"
 }
@@ -1,31 +1,36 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // Inscricao representa a inscrição de um competidor em uma etapa
 type Inscricao struct {
 	BaseModel
-	EtapaID          string      `gorm:"type:uuid;not null;index" json:"etapa_id" binding:"required"`
-	Etapa            *Etapa      `gorm:"foreignKey:EtapaID" json:"etapa,omitempty"`
-	CompetidorID     string      `gorm:"type:uuid;not null;index" json:"competidor_id" binding:"required"`
-	Competidor       *Competidor `gorm:"foreignKey:CompetidorID" json:"competidor,omitempty"`
-	NumeroReguaID    *string     `gorm:"type:uuid;index" json:"numero_regua_id,omitempty"`
-	Regua            *Regua      `gorm:"foreignKey:NumeroReguaID" json:"regua,omitempty"`
-	DataInscricao    time.Time   `gorm:"autoCreateTime;not null" json:"data_inscricao"`
-	ValorPago        float64     `gorm:"type:decimal(10,2)" json:"valor_pago"`
-	StatusPagamento  string      `gorm:"size:20;default:'pendente';index" json:"status_pagamento"`
-	DataPagamento    *time.Time  `json:"data_pagamento,omitempty"`
-	ComprovantePgto  string      `gorm:"size:500" json:"comprovante_pgto,omitempty"`
-	ReguaDevolvida   bool        `gorm:"default:false" json:"regua_devolvida"`
-	DataDevolucao    *time.Time  `json:"data_devolucao,omitempty"`
-	Eliminado        bool        `gorm:"default:false;index" json:"eliminado"`
-	MotivoEliminacao string      `gorm:"type:text" json:"motivo_eliminacao,omitempty"`
-	DataEliminacao   *time.Time  `json:"data_eliminacao,omitempty"`
-	PontuacaoTotal   float64     `gorm:"type:decimal(10,2);default:0" json:"pontuacao_total"`
-	QuantidadePeixes int         `gorm:"default:0" json:"quantidade_peixes"`
+	EtapaID            string      `gorm:"type:uuid;not null;index" json:"etapa_id" binding:"required"`
+	Etapa              *Etapa      `gorm:"foreignKey:EtapaID" json:"etapa,omitempty"`
+	CompetidorID       string      `gorm:"type:uuid;not null;index" json:"competidor_id" binding:"required"`
+	Competidor         *Competidor `gorm:"foreignKey:CompetidorID" json:"competidor,omitempty"`
+	NumeroReguaID      *string     `gorm:"type:uuid;index" json:"numero_regua_id,omitempty"`
+	Regua              *Regua      `gorm:"foreignKey:NumeroReguaID" json:"regua,omitempty"`
+	DataInscricao      time.Time   `gorm:"autoCreateTime;not null" json:"data_inscricao"`
+	ValorPago          float64     `gorm:"type:decimal(10,2)" json:"valor_pago"`
+	StatusPagamento    string      `gorm:"size:20;default:'pendente';index" json:"status_pagamento"`
+	DataPagamento      *time.Time  `json:"data_pagamento,omitempty"`
+	ComprovantePgto    string      `gorm:"size:500" json:"comprovante_pgto,omitempty"`
+	ComprovanteArquivo string      `gorm:"size:500" json:"-"` // chave do comprovante no storage
+	ReguaDevolvida     bool        `gorm:"default:false" json:"regua_devolvida"`
+	DataDevolucao      *time.Time  `json:"data_devolucao,omitempty"`
+	Eliminado          bool        `gorm:"default:false;index" json:"eliminado"`
+	MotivoEliminacao   string      `gorm:"type:text" json:"motivo_eliminacao,omitempty"`
+	DataEliminacao     *time.Time  `json:"data_eliminacao,omitempty"`
+	PontuacaoTotal     float64     `gorm:"type:decimal(10,2);default:0" json:"pontuacao_total"`
+	QuantidadePeixes   int         `gorm:"default:0" json:"quantidade_peixes"`
 
 	// Relacionamentos
-	Capturas []Captura `gorm:"foreignKey:InscricaoID" json:"capturas,omitempty"`
+	Capturas   []Captura             `gorm:"foreignKey:InscricaoID" json:"capturas,omitempty"`
+	Categorias []CategoriaCompetidor `gorm:"many2many:inscricao_categorias;" json:"categorias,omitempty"` // enquadradas na inscrição
 }
 
 // TableName especifica o nome da tabela
@@ -66,19 +71,61 @@ func (i *Inscricao) DevolverRegua() {
 	i.DataDevolucao = &now
 }
 
-// CalcularPontuacao calcula a pontuação total
-func (i *Inscricao) CalcularPontuacao() float64 {
-	total := 0.0
-	count := 0
+// CalcularPontuacao aplica a estratégia aos peixes validados das espécies que entram na
+// cota, pelo peso ou pelo comprimento limitado ao máximo computado pela regra da espécie.
+// Marca ContaCota em cada captura: validada sem ContaCota foi deslocada por um peixe maior.
+func (i *Inscricao) CalcularPontuacao(regras RegrasEspecie, estrategia EstrategiaPontuacao) DetalhePontuacao {
+	var elegiveis []PeixeElegivel
 
-	for _, captura := range i.Capturas {
-		if captura.EstaValidada() && captura.ContaCota && count < CotaMaximaPeixes {
-			total += captura.Tamanho
-			count++
+	for j := range i.Capturas {
+		captura := &i.Capturas[j]
+		captura.ContaCota = false
+
+		regra := regras.Para(captura.Especie)
+		if captura.EstaValidada() && regra.EntraNaCota {
+			computado := captura.MedidaPontuada()
+			if !captura.PorPeso() {
+				computado = regra.TamanhoComputado(computado)
+			}
+			elegiveis = append(elegiveis, PeixeElegivel{Captura: captura, Computado: computado})
+		}
+	}
+
+	// Maior primeiro; no empate vale o peixe capturado antes
+	sort.SliceStable(elegiveis, func(a, b int) bool {
+		if elegiveis[a].Computado != elegiveis[b].Computado {
+			return elegiveis[a].Computado > elegiveis[b].Computado
 		}
+		return elegiveis[a].Captura.HoraCaptura.Before(elegiveis[b].Captura.HoraCaptura)
+	})
+
+	unidade := UnidadeComprimento
+	if i.Etapa != nil {
+		unidade = i.Etapa.Unidade()
 	}
 
-	i.PontuacaoTotal = total
-	i.QuantidadePeixes = count
-	return total
+	detalhe := estrategia.Pontuar(elegiveis, unidade)
+
+	contam := map[string]bool{}
+	for _, item := range detalhe.Itens {
+		contam[item.CapturaID] = true
+	}
+	for _, peixe := range elegiveis {
+		peixe.Captura.ContaCota = contam[peixe.Captura.ID.String()]
+	}
+
+	i.PontuacaoTotal = detalhe.Total
+	i.QuantidadePeixes = len(detalhe.Itens)
+	return detalhe
+}
+
+// CapturasDeslocadas retorna as capturas validadas que saíram da cota por peixes maiores
+func (i *Inscricao) CapturasDeslocadas(regras RegrasEspecie) []Captura {
+	var deslocadas []Captura
+	for _, captura := range i.Capturas {
+		if captura.EstaValidada() && regras.Para(captura.Especie).EntraNaCota && !captura.ContaCota {
+			deslocadas = append(deslocadas, captura)
+		}
+	}
+	return deslocadas
 }
@@ -7,6 +7,10 @@ type Modalidade struct {
 	IconeURL  string `gorm:"size:500" json:"icone_url"`
 	Ativa     bool   `gorm:"default:true" json:"ativa"`
 	Ordem     int    `gorm:"default:0" json:"ordem"` // para ordenação na exibição
+
+	// Pontuação padrão das etapas da modalidade
+	EstrategiaPontuacao string  `gorm:"size:30;default:'soma_comprimentos'" json:"estrategia_pontuacao"`
+	PontosPorPeixe      float64 `gorm:"type:decimal(10,2);default:0" json:"pontos_por_peixe"` // usado por pontos_por_peixe
 }
 
 func (Modalidade) TableName() string {
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Penalidade é uma infração do catálogo de penalidades de uma edição
+type Penalidade struct {
+	BaseModel
+	EdicaoID   uuid.UUID `gorm:"type:uuid;not null;index:idx_penalidade_edicao_codigo" json:"edicao_id" binding:"required"`
+	Edicao     *Edicao   `gorm:"foreignKey:EdicaoID" json:"edicao,omitempty"`
+	Codigo     string    `gorm:"size:30;not null;index:idx_penalidade_edicao_codigo" json:"codigo" binding:"required"`
+	Descricao  string    `gorm:"size:200;not null" json:"descricao" binding:"required"`
+	Valor      float64   `gorm:"type:decimal(10,2);not null" json:"valor" binding:"required,gt=0"` // desconto na unidade
+	Unidade    string    `gorm:"size:5;default:'cm'" json:"unidade"`                               // cm ou g, conforme a medição da etapa
+	Cumulativa bool      `gorm:"default:true" json:"cumulativa"`                                   // pode ser somada a outras penalidades
+	Ativa      bool      `gorm:"default:true" json:"ativa"`
+}
+
+// TableName especifica o nome da tabela
+func (Penalidade) TableName() string {
+	return "penalidades"
+}
+
+// CapturaPenalidade registra cada penalidade do catálogo aplicada a uma captura
+type CapturaPenalidade struct {
+	BaseModel
+	CapturaID    string    `gorm:"type:uuid;not null;index" json:"captura_id"`
+	PenalidadeID uuid.UUID `gorm:"type:uuid;not null;index" json:"penalidade_id"`
+	Codigo       string    `gorm:"size:30;not null;index" json:"codigo"`
+	Descricao    string    `gorm:"size:200" json:"descricao"`
+	Valor        float64   `gorm:"type:decimal(10,2)" json:"valor"`
+	Unidade      string    `gorm:"size:5;default:'cm'" json:"unidade"`
+	AplicadaPor  string    `gorm:"size:100" json:"aplicada_por"`
+}
+
+// TableName especifica o nome da tabela
+func (CapturaPenalidade) TableName() string {
+	return "captura_penalidades"
+}
+
+// UnidadeOuPadrao retorna a unidade da penalidade; registros antigos são em cm
+func (p *Penalidade) UnidadeOuPadrao() string {
+	if p.Unidade == "" {
+		return UnidadeComprimento
+	}
+	return p.Unidade
+}
+
+// AplicarPenalidades confere os códigos contra o catálogo e soma os valores.
+// Só valem penalidades na unidade da etapa; as não cumulativas não podem ser
+// combinadas com outras, e o total é limitado ao máximo da unidade (limitado = true
+// quando a soma passou do máximo).
+func AplicarPenalidades(catalogo []Penalidade, codigos []string, unidade string) (aplicadas []CapturaPenalidade, total float64, limitado bool, err error) {
+	porCodigo := make(map[string]Penalidade, len(catalogo))
+	for _, p := range catalogo {
+		if p.Ativa {
+			porCodigo[p.Codigo] = p
+		}
+	}
+
+	vistos := map[string]bool{}
+
+	for _, codigo := range codigos {
+		codigo = strings.TrimSpace(codigo)
+
+		p, ok := porCodigo[codigo]
+		if !ok {
+			return nil, 0, false, fmt.Errorf("penalidade desconhecida: %s", codigo)
+		}
+
+		if p.UnidadeOuPadrao() != unidade {
+			return nil, 0, false, fmt.Errorf("penalidade %s é em %s, mas a etapa mede em %s", codigo, p.UnidadeOuPadrao(), unidade)
+		}
+
+		if vistos[codigo] {
+			return nil, 0, false, fmt.Errorf("penalidade repetida: %s", codigo)
+		}
+		vistos[codigo] = true
+
+		if !p.Cumulativa && len(codigos) > 1 {
+			return nil, 0, false, fmt.Errorf("penalidade %s não pode ser combinada com outras", codigo)
+		}
+
+		aplicadas = append(aplicadas, CapturaPenalidade{
+			PenalidadeID: p.ID,
+			Codigo:       p.Codigo,
+			Descricao:    p.Descricao,
+			Valor:        p.Valor,
+			Unidade:      p.UnidadeOuPadrao(),
+		})
+		total += p.Valor
+	}
+
+	if total > PenalidadeMaximaEm(unidade) {
+		total = PenalidadeMaximaEm(unidade)
+		limitado = true
+	}
+
+	return aplicadas, total, limitado, nil
+}
+
+// DescreverPenalidades monta o motivo a partir das penalidades aplicadas
+func DescreverPenalidades(aplicadas []CapturaPenalidade) string {
+	descricoes := make([]string, len(aplicadas))
+	for i, p := range aplicadas {
+		unidade := p.Unidade
+		if unidade == "" {
+			unidade = UnidadeComprimento
+		}
+		descricoes[i] = fmt.Sprintf("%s (%.1f %s)", p.Descricao, p.Valor, unidade)
+	}
+	return strings.Join(descricoes, "; ")
+}
+
+// PenalidadesEmComum retorna as penalidades de segunda que a primeira medição também aplicou.
+// Na validação dupla só os códigos em que os dois fiscais concordam ficam no detalhamento;
+// o valor da penalidade continua sendo a média dos dois, que pode diferir dessa soma.
+func PenalidadesEmComum(primeira, segunda []CapturaPenalidade) []CapturaPenalidade {
+	codigos := make(map[string]bool, len(primeira))
+	for _, p := range primeira {
+		codigos[p.Codigo] = true
+	}
+
+	var comuns []CapturaPenalidade
+	for _, p := range segunda {
+		if codigos[p.Codigo] {
+			comuns = append(comuns, p)
+		}
+	}
+	return comuns
+}
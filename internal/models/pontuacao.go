@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PeixeElegivel é uma captura validada que disputa a pontuação, com o peso ou a
+// medida já limitada pela regra da espécie
+type PeixeElegivel struct {
+	Captura   *Captura
+	Computado float64
+}
+
+// ItemPontuacao é o quanto uma captura valeu na pontuação
+type ItemPontuacao struct {
+	CapturaID string  `json:"captura_id"`
+	Especie   string  `json:"especie"`
+	Tamanho   float64 `json:"tamanho"` // medida computada, na unidade do detalhe
+	Pontos    float64 `json:"pontos"`
+}
+
+// DetalhePontuacao explica como a pontuação de uma inscrição foi formada
+type DetalhePontuacao struct {
+	Estrategia string          `json:"estrategia"`
+	Unidade    string          `json:"unidade"` // cm ou g
+	Itens      []ItemPontuacao `json:"itens"`
+	Total      float64         `json:"total"`
+	Explicacao string          `json:"explicacao"`
+}
+
+// EstrategiaPontuacao define como os peixes elegíveis viram pontos.
+// Os elegíveis chegam ordenados do maior para o menor, medidos na unidade
+// informada; os que aparecem nos itens do detalhe são os que contam na cota.
+type EstrategiaPontuacao interface {
+	Codigo() string
+	Pontuar(elegiveis []PeixeElegivel, unidade string) DetalhePontuacao
+}
+
+// NovaEstrategiaPontuacao cria a estratégia pelo código; vazio é a soma de comprimentos
+func NovaEstrategiaPontuacao(codigo string, pontosPorPeixe float64) (EstrategiaPontuacao, error) {
+	switch codigo {
+	case "", EstrategiaSomaComprimentos:
+		return SomaComprimentos{Cota: CotaMaximaPeixes}, nil
+	case EstrategiaPontosPorPeixe:
+		if pontosPorPeixe <= 0 {
+			return nil, fmt.Errorf("estratégia %s exige pontos por peixe maior que zero", codigo)
+		}
+		return PontosPorPeixe{Cota: CotaMaximaPeixes, Pontos: pontosPorPeixe}, nil
+	case EstrategiaMaiorPeixe:
+		return MaiorPeixe{}, nil
+	}
+	return nil, fmt.Errorf("estratégia de pontuação inválida: %s", codigo)
+}
+
+// EscolherEstrategia usa a estratégia da etapa ou, se não definida, a da modalidade
+// (que precisa estar carregada). Configuração inválida cai na soma de comprimentos.
+func EscolherEstrategia(etapa *Etapa) EstrategiaPontuacao {
+	if etapa != nil {
+		if etapa.EstrategiaPontuacao != "" {
+			if estrategia, err := NovaEstrategiaPontuacao(etapa.EstrategiaPontuacao, etapa.PontosPorPeixe); err == nil {
+				return estrategia
+			}
+		} else if etapa.Modalidade != nil {
+			if estrategia, err := NovaEstrategiaPontuacao(etapa.Modalidade.EstrategiaPontuacao, etapa.Modalidade.PontosPorPeixe); err == nil {
+				return estrategia
+			}
+		}
+	}
+	return SomaComprimentos{Cota: CotaMaximaPeixes}
+}
+
+// CotaEstrategia retorna quantos peixes contam na pontuação da estratégia
+func CotaEstrategia(estrategia EstrategiaPontuacao) int {
+	switch e := estrategia.(type) {
+	case SomaComprimentos:
+		return e.Cota
+	case PontosPorPeixe:
+		return e.Cota
+	case MaiorPeixe:
+		return 1
+	}
+	return CotaMaximaPeixes
+}
+
+// SomaComprimentos soma as medidas dos maiores peixes até a cota (ou os pesos, nas etapas por peso)
+type SomaComprimentos struct {
+	Cota int
+}
+
+// Codigo identifica a estratégia
+func (SomaComprimentos) Codigo() string {
+	return EstrategiaSomaComprimentos
+}
+
+// Pontuar soma as medidas dos Cota maiores peixes
+func (s SomaComprimentos) Pontuar(elegiveis []PeixeElegivel, unidade string) DetalhePontuacao {
+	detalhe := DetalhePontuacao{Estrategia: s.Codigo(), Unidade: unidade}
+
+	var parcelas []string
+	for _, peixe := range limitarCota(elegiveis, s.Cota) {
+		detalhe.adicionar(peixe, peixe.Computado)
+		parcelas = append(parcelas, fmt.Sprintf("%.2f", peixe.Computado))
+	}
+
+	detalhe.Explicacao = fmt.Sprintf("Soma dos %d maiores peixes (cota %d): %s = %.2f %s",
+		len(detalhe.Itens), s.Cota, juntarParcelas(parcelas), detalhe.Total, unidade)
+	return detalhe
+}
+
+// PontosPorPeixe dá uma pontuação fixa a cada peixe até a cota; a medida só desempata a escolha
+type PontosPorPeixe struct {
+	Cota   int
+	Pontos float64
+}
+
+// Codigo identifica a estratégia
+func (PontosPorPeixe) Codigo() string {
+	return EstrategiaPontosPorPeixe
+}
+
+// Pontuar atribui Pontos a cada um dos Cota maiores peixes
+func (p PontosPorPeixe) Pontuar(elegiveis []PeixeElegivel, unidade string) DetalhePontuacao {
+	detalhe := DetalhePontuacao{Estrategia: p.Codigo(), Unidade: unidade}
+
+	for _, peixe := range limitarCota(elegiveis, p.Cota) {
+		detalhe.adicionar(peixe, p.Pontos)
+	}
+
+	detalhe.Explicacao = fmt.Sprintf("%d peixes × %.2f pontos (cota %d) = %.2f pontos",
+		len(detalhe.Itens), p.Pontos, p.Cota, detalhe.Total)
+	return detalhe
+}
+
+// MaiorPeixe pontua apenas a medida do maior peixe
+type MaiorPeixe struct{}
+
+// Codigo identifica a estratégia
+func (MaiorPeixe) Codigo() string {
+	return EstrategiaMaiorPeixe
+}
+
+// Pontuar usa somente o maior peixe
+func (m MaiorPeixe) Pontuar(elegiveis []PeixeElegivel, unidade string) DetalhePontuacao {
+	detalhe := DetalhePontuacao{Estrategia: m.Codigo(), Unidade: unidade}
+
+	if len(elegiveis) == 0 {
+		detalhe.Explicacao = "Nenhum peixe validado"
+		return detalhe
+	}
+
+	detalhe.adicionar(elegiveis[0], elegiveis[0].Computado)
+	detalhe.Explicacao = fmt.Sprintf("Maior peixe: %.2f %s", detalhe.Total, unidade)
+	return detalhe
+}
+
+// adicionar registra um peixe no detalhe e soma seus pontos
+func (d *DetalhePontuacao) adicionar(peixe PeixeElegivel, pontos float64) {
+	d.Itens = append(d.Itens, ItemPontuacao{
+		CapturaID: peixe.Captura.ID.String(),
+		Especie:   peixe.Captura.Especie,
+		Tamanho:   peixe.Computado,
+		Pontos:    pontos,
+	})
+	d.Total += pontos
+}
+
+// MaiorComputado retorna a maior medida entre os peixes que contaram na cota, já limitada
+// pela regra da espécie; é o "maior peixe" usado no desempate
+func (d *DetalhePontuacao) MaiorComputado() float64 {
+	maior := 0.0
+	for _, item := range d.Itens {
+		if item.Tamanho > maior {
+			maior = item.Tamanho
+		}
+	}
+	return maior
+}
+
+// limitarCota retorna no máximo cota peixes do início da lista
+func limitarCota(elegiveis []PeixeElegivel, cota int) []PeixeElegivel {
+	if len(elegiveis) > cota {
+		return elegiveis[:cota]
+	}
+	return elegiveis
+}
+
+// juntarParcelas monta "a + b + c" para a explicação
+func juntarParcelas(parcelas []string) string {
+	if len(parcelas) == 0 {
+		return "0"
+	}
+	return strings.Join(parcelas, " + ")
+}
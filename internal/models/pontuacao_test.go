@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func capturaValidada(especie string, tamanho float64, minuto int) Captura {
+	return Captura{
+		BaseModel:   BaseModel{ID: uuid.New()},
+		Especie:     especie,
+		Unidade:     UnidadeComprimento,
+		Tamanho:     tamanho,
+		Validado:    true,
+		HoraCaptura: time.Date(2026, 3, 1, 8, minuto, 0, 0, time.UTC),
+	}
+}
+
+func TestMaiorComputado(t *testing.T) {
+	limite := RegrasEspecie{
+		EspecieTucunareAzul: {Especie: EspecieTucunareAzul, TamanhoMaximo: 50, EntraNaCota: true},
+	}
+
+	casos := []struct {
+		nome       string
+		regras     RegrasEspecie
+		estrategia EstrategiaPontuacao
+		capturas   []Captura
+		esperado   float64
+	}{
+		{
+			nome:       "traíra não entra na cota e não vale como maior peixe",
+			regras:     RegrasEspecie{},
+			estrategia: SomaComprimentos{Cota: CotaMaximaPeixes},
+			capturas: []Captura{
+				capturaValidada(EspecieTucunareAzul, 35, 1),
+				capturaValidada(EspecieTraira, 60, 2),
+			},
+			esperado: 35,
+		},
+		{
+			nome:       "medida limitada pelo tamanho máximo da regra",
+			regras:     limite,
+			estrategia: SomaComprimentos{Cota: CotaMaximaPeixes},
+			capturas: []Captura{
+				capturaValidada(EspecieTucunareAzul, 62, 1),
+				capturaValidada(EspecieTucunareAzul, 40, 2),
+			},
+			esperado: 50,
+		},
+		{
+			nome:       "peixe fora da cota não conta",
+			regras:     RegrasEspecie{},
+			estrategia: SomaComprimentos{Cota: 1},
+			capturas: []Captura{
+				capturaValidada(EspecieTucunareAzul, 30, 1),
+				capturaValidada(EspecieTraira, 45, 2),
+				capturaValidada(EspecieTucunareAmarelo, 38, 3),
+			},
+			esperado: 38,
+		},
+		{
+			nome:       "captura anulada não conta",
+			regras:     RegrasEspecie{},
+			estrategia: SomaComprimentos{Cota: CotaMaximaPeixes},
+			capturas: func() []Captura {
+				anulada := capturaValidada(EspecieTucunareAzul, 55, 1)
+				anulada.Anulado = true
+				return []Captura{anulada, capturaValidada(EspecieTucunareAzul, 33, 2)}
+			}(),
+			esperado: 33,
+		},
+		{
+			nome:       "sem peixes elegíveis",
+			regras:     RegrasEspecie{},
+			estrategia: SomaComprimentos{Cota: CotaMaximaPeixes},
+			capturas:   []Captura{capturaValidada(EspecieTraira, 70, 1)},
+			esperado:   0,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			inscricao := Inscricao{Capturas: caso.capturas}
+			detalhe := inscricao.CalcularPontuacao(caso.regras, caso.estrategia)
+
+			if obtido := detalhe.MaiorComputado(); obtido != caso.esperado {
+				t.Errorf("maior peixe esperado %.2f, obtido %.2f", caso.esperado, obtido)
+			}
+		})
+	}
+}
+
+func TestCotaEstrategia(t *testing.T) {
+	casos := []struct {
+		nome     string
+		etapa    *Etapa
+		esperado int
+	}{
+		{nome: "padrão", etapa: &Etapa{}, esperado: CotaMaximaPeixes},
+		{nome: "pontos por peixe", etapa: &Etapa{EstrategiaPontuacao: EstrategiaPontosPorPeixe, PontosPorPeixe: 10}, esperado: CotaMaximaPeixes},
+		{nome: "maior peixe", etapa: &Etapa{EstrategiaPontuacao: EstrategiaMaiorPeixe}, esperado: 1},
+		{nome: "herdada da modalidade", etapa: &Etapa{Modalidade: &Modalidade{EstrategiaPontuacao: EstrategiaMaiorPeixe}}, esperado: 1},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			if obtido := CotaEstrategia(EscolherEstrategia(caso.etapa)); obtido != caso.esperado {
+				t.Errorf("cota esperada %d, obtida %d", caso.esperado, obtido)
+			}
+		})
+	}
+}
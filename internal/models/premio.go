@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Premio é uma faixa da tabela de premiação de uma etapa em uma categoria do ranking
+// (geral, maior_<espécie> ou código da categoria de competidor)
+type Premio struct {
+	BaseModel
+	EtapaID        string  `gorm:"type:uuid;not null;index:idx_premio_etapa_categoria" json:"etapa_id" binding:"required"`
+	Etapa          *Etapa  `gorm:"foreignKey:EtapaID" json:"etapa,omitempty"`
+	Categoria      string  `gorm:"size:30;not null;index:idx_premio_etapa_categoria" json:"categoria" binding:"required"`
+	PosicaoInicial int     `gorm:"not null" json:"posicao_inicial" binding:"required,min=1"`
+	PosicaoFinal   int     `gorm:"not null" json:"posicao_final" binding:"min=0"` // 0 = só a posição inicial
+	Descricao      string  `gorm:"size:200;not null" json:"descricao" binding:"required"`
+	Valor          float64 `gorm:"type:decimal(10,2);default:0" json:"valor" binding:"min=0"`
+	Trofeu         bool    `gorm:"default:false" json:"trofeu"`
+}
+
+// TableName especifica o nome da tabela
+func (Premio) TableName() string {
+	return "premios"
+}
+
+// Normalizar completa a posição final e confere a faixa
+func (p *Premio) Normalizar() error {
+	if p.PosicaoFinal == 0 {
+		p.PosicaoFinal = p.PosicaoInicial
+	}
+	if p.PosicaoFinal < p.PosicaoInicial {
+		return errors.New("posição final não pode ser menor que a inicial")
+	}
+	return nil
+}
+
+// Abrange verifica se a posição está na faixa do prêmio
+func (p *Premio) Abrange(posicao int) bool {
+	return posicao >= p.PosicaoInicial && posicao <= p.PosicaoFinal
+}
+
+// Sobrepoe verifica se as faixas de dois prêmios da mesma categoria se cruzam
+func (p *Premio) Sobrepoe(outro *Premio) bool {
+	return p.Categoria == outro.Categoria &&
+		p.PosicaoInicial <= outro.PosicaoFinal && outro.PosicaoInicial <= p.PosicaoFinal
+}
+
+// TabelaPremios é a premiação configurada para uma etapa
+type TabelaPremios []Premio
+
+// Para retorna o prêmio da categoria para a posição, ou nil
+func (t TabelaPremios) Para(categoria string, posicao int) *Premio {
+	for i := range t {
+		if t[i].Categoria == categoria && t[i].Abrange(posicao) {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+// TemCategoria indica se a tabela define prêmios para a categoria
+func (t TabelaPremios) TemCategoria(categoria string) bool {
+	for _, premio := range t {
+		if premio.Categoria == categoria {
+			return true
+		}
+	}
+	return false
+}
+
+// Premiar preenche o prêmio da linha do ranking; empatados é quantas linhas dividem a posição
+// (1 sem empate). Os empatados somam os valores das posições que ocupam juntos e dividem
+// igualmente, e a descrição e o troféu são os da posição. Sem tabela para a categoria,
+// os três primeiros recebem a colocação por extenso.
+func (t TabelaPremios) Premiar(ranking *Ranking, empatados int, nomeCategoria string) {
+	ranking.Premiacao, ranking.ValorPremiacao, ranking.Trofeu = "", 0, false
+
+	if t.TemCategoria(ranking.Categoria) {
+		premio := t.Para(ranking.Categoria, ranking.Posicao)
+		if premio == nil {
+			return
+		}
+		ranking.Premiacao = premio.Descricao
+		ranking.Trofeu = premio.Trofeu
+
+		if empatados < 1 {
+			empatados = 1
+		}
+		var soma float64
+		for posicao := ranking.Posicao; posicao < ranking.Posicao+empatados; posicao++ {
+			if ocupada := t.Para(ranking.Categoria, posicao); ocupada != nil {
+				soma += ocupada.Valor
+			}
+		}
+		// Em centavos, arredondando para baixo para a soma não passar do total
+		centavos := math.Round(soma * 100)
+		ranking.ValorPremiacao = math.Floor(centavos/float64(empatados)) / 100
+		return
+	}
+
+	if ranking.Posicao <= 3 {
+		ranking.Premiacao = fmt.Sprintf("%dº Lugar", ranking.Posicao)
+		if nomeCategoria != "" {
+			ranking.Premiacao += " - " + nomeCategoria
+		}
+	}
+}
+
+// Premiado indica se a linha do ranking recebeu prêmio
+func (r *Ranking) Premiado() bool {
+	return r.Premiacao != "" || r.ValorPremiacao > 0 || r.Trofeu
+}
+
+// RegistrarPagamento marca o prêmio da linha como pago (ou desfaz a marcação)
+func (r *Ranking) RegistrarPagamento(pago bool, data *time.Time, forma, pagoPor string) {
+	if !pago {
+		r.PremioPago, r.DataPagamento, r.FormaPagamento, r.PagoPor = false, nil, "", ""
+		return
+	}
+
+	if data == nil {
+		agora := time.Now()
+		data = &agora
+	}
+	r.PremioPago, r.DataPagamento, r.FormaPagamento, r.PagoPor = true, data, forma, pagoPor
+}
+
+// HerdarPagamento copia o pagamento já registrado na versão anterior para a mesma premiação
+func (r *Ranking) HerdarPagamento(anterior *Ranking) {
+	if anterior.PremioPago && r.Premiado() {
+		r.PremioPago = true
+		r.DataPagamento = anterior.DataPagamento
+		r.FormaPagamento = anterior.FormaPagamento
+		r.PagoPor = anterior.PagoPor
+	}
+}
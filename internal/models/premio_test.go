@@ -0,0 +1,71 @@
+package models
+
+import "testing"
+
+func TestPremiar(t *testing.T) {
+	tabela := TabelaPremios{
+		{Categoria: CategoriaGeral, PosicaoInicial: 1, PosicaoFinal: 1, Descricao: "Campeão", Valor: 1000, Trofeu: true},
+		{Categoria: CategoriaGeral, PosicaoInicial: 2, PosicaoFinal: 2, Descricao: "Vice", Valor: 500, Trofeu: true},
+		{Categoria: CategoriaGeral, PosicaoInicial: 3, PosicaoFinal: 5, Descricao: "Top 5", Valor: 100},
+	}
+
+	casos := []struct {
+		nome          string
+		categoria     string
+		posicao       int
+		empatados     int
+		nomeCategoria string
+		premiacao     string
+		valor         float64
+		trofeu        bool
+	}{
+		{
+			nome: "sem empate", categoria: CategoriaGeral, posicao: 1, empatados: 1,
+			premiacao: "Campeão", valor: 1000, trofeu: true,
+		},
+		{
+			nome: "dois empatados em primeiro dividem primeiro e segundo", categoria: CategoriaGeral, posicao: 1, empatados: 2,
+			premiacao: "Campeão", valor: 750, trofeu: true,
+		},
+		{
+			nome: "três empatados em primeiro, divisão arredondada para baixo", categoria: CategoriaGeral, posicao: 1, empatados: 3,
+			premiacao: "Campeão", valor: 533.33, trofeu: true,
+		},
+		{
+			nome: "empate além da tabela só soma as posições premiadas", categoria: CategoriaGeral, posicao: 5, empatados: 2,
+			premiacao: "Top 5", valor: 50,
+		},
+		{
+			nome: "empatados sem informar contam como um", categoria: CategoriaGeral, posicao: 2, empatados: 0,
+			premiacao: "Vice", valor: 500, trofeu: true,
+		},
+		{
+			nome: "posição fora da tabela", categoria: CategoriaGeral, posicao: 6, empatados: 1,
+		},
+		{
+			nome: "categoria sem tabela recebe a colocação", categoria: "feminino", posicao: 2, empatados: 2,
+			nomeCategoria: "Feminino", premiacao: "2º Lugar - Feminino",
+		},
+		{
+			nome: "categoria sem tabela só premia até o terceiro", categoria: "feminino", posicao: 4, empatados: 1,
+			nomeCategoria: "Feminino",
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			ranking := Ranking{Categoria: caso.categoria, Posicao: caso.posicao, ValorPremiacao: 99, Trofeu: true}
+			tabela.Premiar(&ranking, caso.empatados, caso.nomeCategoria)
+
+			if ranking.Premiacao != caso.premiacao {
+				t.Errorf("premiação esperada %q, obtida %q", caso.premiacao, ranking.Premiacao)
+			}
+			if ranking.ValorPremiacao != caso.valor {
+				t.Errorf("valor esperado %.2f, obtido %.2f", caso.valor, ranking.ValorPremiacao)
+			}
+			if ranking.Trofeu != caso.trofeu {
+				t.Errorf("troféu esperado %v, obtido %v", caso.trofeu, ranking.Trofeu)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// PublicacaoRanking é uma versão oficial do ranking de uma etapa. As linhas publicadas são
+// cópias congeladas do ranking provisório e não mudam mais: correções geram nova versão.
+type PublicacaoRanking struct {
+	BaseModel
+	EtapaID        string    `gorm:"type:uuid;not null;uniqueIndex:idx_publicacao_etapa_versao" json:"etapa_id"`
+	Etapa          *Etapa    `gorm:"foreignKey:EtapaID" json:"etapa,omitempty"`
+	Versao         int       `gorm:"not null;uniqueIndex:idx_publicacao_etapa_versao" json:"versao"`
+	PublicadoPor   string    `gorm:"size:100" json:"publicado_por"`
+	PublicadoPorID string    `gorm:"type:uuid" json:"publicado_por_id"`
+	PublicadoEm    time.Time `gorm:"not null" json:"publicado_em"`
+	Motivo         string    `gorm:"type:text" json:"motivo,omitempty"` // obrigatório nas correções
+	TotalLinhas    int       `json:"total_linhas"`
+}
+
+// TableName especifica o nome da tabela
+func (PublicacaoRanking) TableName() string {
+	return "publicacoes_ranking"
+}
+
+// NovaPublicacao prepara a versão seguinte à última publicada (0 se nunca houve publicação)
+func NovaPublicacao(etapaID string, ultimaVersao int, motivo, publicadoPor, publicadoPorID string) (*PublicacaoRanking, error) {
+	motivo = strings.TrimSpace(motivo)
+	if ultimaVersao > 0 && motivo == "" {
+		return nil, errors.New("informe o motivo da correção do ranking oficial")
+	}
+
+	return &PublicacaoRanking{
+		EtapaID:        etapaID,
+		Versao:         ultimaVersao + 1,
+		PublicadoPor:   publicadoPor,
+		PublicadoPorID: publicadoPorID,
+		PublicadoEm:    time.Now(),
+		Motivo:         motivo,
+	}, nil
+}
+
+// Congelar copia as linhas do ranking provisório para esta versão
+func (p *PublicacaoRanking) Congelar(provisorio []Ranking) []Ranking {
+	publicacaoID := p.ID.String()
+
+	linhas := make([]Ranking, len(provisorio))
+	for i, ranking := range provisorio {
+		ranking.BaseModel = BaseModel{}
+		ranking.Etapa = nil
+		ranking.Inscricao = nil
+		ranking.Versao = p.Versao
+		ranking.PublicacaoID = &publicacaoID
+		linhas[i] = ranking
+	}
+
+	p.TotalLinhas = len(linhas)
+	return linhas
+}
+
+// Oficial indica se a linha pertence a uma versão publicada (imutável)
+func (r *Ranking) Oficial() bool {
+	return r.Versao > 0
+}
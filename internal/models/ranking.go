@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Ranking representa a classificação
 type Ranking struct {
 	BaseModel
@@ -8,12 +10,30 @@ type Ranking struct {
 	InscricaoID      string     `gorm:"type:uuid;not null;index" json:"inscricao_id"`
 	Inscricao        *Inscricao `gorm:"foreignKey:InscricaoID" json:"inscricao,omitempty"`
 	Posicao          int        `gorm:"not null;index" json:"posicao"`
+	Empatado         bool       `gorm:"default:false" json:"empatado"` // divide a posição com outro competidor
 	PontuacaoTotal   float64    `gorm:"type:decimal(10,2)" json:"pontuacao_total"`
 	MaiorPeixe       float64    `gorm:"type:decimal(10,2)" json:"maior_peixe"`
+	Unidade          string     `gorm:"size:5;default:'cm'" json:"unidade"` // unidade da pontuação e do maior peixe
 	QuantidadePeixes int        `json:"quantidade_peixes"`
 	Categoria        string     `gorm:"size:30;index" json:"categoria"`
 	Premiacao        string     `gorm:"size:200" json:"premiacao,omitempty"`
 	ValorPremiacao   float64    `gorm:"type:decimal(10,2)" json:"valor_premiacao,omitempty"`
+	Trofeu           bool       `gorm:"default:false" json:"trofeu"`
+	Estrategia       string     `gorm:"size:30" json:"estrategia,omitempty"`
+	Explicacao       string     `gorm:"type:text" json:"explicacao,omitempty"` // como a pontuação foi formada
+
+	// Critério que separou o competidor do anterior com a mesma pontuação
+	CriterioDesempate string `gorm:"size:30" json:"criterio_desempate,omitempty"`
+
+	// Versão oficial a que a linha pertence; 0 é o ranking provisório, que pode ser gerado de novo
+	Versao       int     `gorm:"default:0;index" json:"versao"`
+	PublicacaoID *string `gorm:"type:uuid;index" json:"publicacao_id,omitempty"`
+
+	// Pagamento do prêmio (só nas versões oficiais; passa para as correções)
+	PremioPago     bool       `gorm:"default:false" json:"premio_pago"`
+	DataPagamento  *time.Time `json:"data_pagamento,omitempty"`
+	FormaPagamento string     `gorm:"size:20" json:"forma_pagamento,omitempty"`
+	PagoPor        string     `gorm:"size:100" json:"pago_por,omitempty"`
 }
 
 func (Ranking) TableName() string {
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Recurso é a contestação de um competidor contra a anulação ou a penalidade de uma captura
+type Recurso struct {
+	BaseModel
+	CapturaID    string     `gorm:"type:uuid;not null;index" json:"captura_id" binding:"required"`
+	Captura      *Captura   `gorm:"foreignKey:CapturaID" json:"captura,omitempty"`
+	InscricaoID  string     `gorm:"type:uuid;not null;index" json:"inscricao_id"`
+	Inscricao    *Inscricao `gorm:"foreignKey:InscricaoID" json:"inscricao,omitempty"`
+	Tipo         string     `gorm:"size:20;not null" json:"tipo"` // anulacao, penalidade
+	Status       string     `gorm:"size:20;default:'aberto';index" json:"status"`
+	Motivo       string     `gorm:"type:text;not null" json:"motivo" binding:"required"`
+	AbertoPor    string     `gorm:"size:100" json:"aberto_por"`
+	AnalisadoPor string     `gorm:"size:100" json:"analisado_por,omitempty"`
+	DataAnalise  *time.Time `json:"data_analise,omitempty"`
+	Decisao      string     `gorm:"type:text" json:"decisao,omitempty"`
+	DecididoPor  string     `gorm:"size:100" json:"decidido_por,omitempty"`
+	DataDecisao  *time.Time `json:"data_decisao,omitempty"`
+
+	// Situação contestada, guardada para histórico mesmo após a reversão
+	PenalidadeContestada float64 `gorm:"type:decimal(10,2)" json:"penalidade_contestada"`
+	MotivoContestado     string  `gorm:"type:text" json:"motivo_contestado,omitempty"`
+}
+
+// TableName especifica o nome da tabela
+func (Recurso) TableName() string {
+	return "recursos"
+}
+
+// NovoRecurso prepara um recurso contra a situação atual da captura
+func NovoRecurso(captura *Captura, motivo, abertoPor string) (*Recurso, error) {
+	recurso := &Recurso{
+		CapturaID:   captura.ID.String(),
+		InscricaoID: captura.InscricaoID,
+		Status:      StatusRecursoAberto,
+		Motivo:      motivo,
+		AbertoPor:   abertoPor,
+	}
+
+	switch {
+	case captura.Anulado:
+		recurso.Tipo = TipoRecursoAnulacao
+		recurso.MotivoContestado = captura.MotivoAnulacao
+	case captura.Validado && captura.Penalidade > 0:
+		recurso.Tipo = TipoRecursoPenalidade
+		recurso.PenalidadeContestada = captura.Penalidade
+		recurso.MotivoContestado = captura.MotivoPenalidade
+	default:
+		return nil, errors.New("captura não foi anulada nem penalizada")
+	}
+
+	return recurso, nil
+}
+
+// EstaPendente verifica se o recurso ainda aguarda decisão
+func (r *Recurso) EstaPendente() bool {
+	return r.Status == StatusRecursoAberto || r.Status == StatusRecursoEmAnalise
+}
+
+// IniciarAnalise marca o recurso como em análise
+func (r *Recurso) IniciarAnalise(analisadoPor string) {
+	r.Status = StatusRecursoEmAnalise
+	r.AnalisadoPor = analisadoPor
+	now := time.Now()
+	r.DataAnalise = &now
+}
+
+// Decidir registra a decisão do organizador
+func (r *Recurso) Decidir(deferido bool, decisao, decididoPor string) {
+	r.Status = StatusRecursoIndeferido
+	if deferido {
+		r.Status = StatusRecursoDeferido
+	}
+	r.Decisao = decisao
+	r.DecididoPor = decididoPor
+	now := time.Now()
+	r.DataDecisao = &now
+}
+
+// Reverter desfaz na captura a anulação ou a penalidade contestada
+func (r *Recurso) Reverter(captura *Captura) {
+	switch r.Tipo {
+	case TipoRecursoAnulacao:
+		captura.Anulado = false
+		captura.MotivoAnulacao = ""
+	case TipoRecursoPenalidade:
+		captura.Penalidade = 0
+		captura.MotivoPenalidade = ""
+		captura.Tamanho = captura.CalcularTamanhoFinal()
+		captura.Peso = captura.CalcularPesoFinal()
+	}
+}
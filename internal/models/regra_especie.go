@@ -0,0 +1,97 @@
+package models
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// RegraEspecie define os limites de uma espécie em uma edição, modalidade ou etapa.
+// Vale a regra mais específica: etapa > modalidade > edição > padrão do regulamento.
+type RegraEspecie struct {
+	BaseModel
+	EdicaoID      *uuid.UUID  `gorm:"type:uuid;index" json:"edicao_id,omitempty"`
+	Edicao        *Edicao     `gorm:"foreignKey:EdicaoID" json:"edicao,omitempty"`
+	ModalidadeID  *uuid.UUID  `gorm:"type:uuid;index" json:"modalidade_id,omitempty"`
+	Modalidade    *Modalidade `gorm:"foreignKey:ModalidadeID" json:"modalidade,omitempty"`
+	EtapaID       *uuid.UUID  `gorm:"type:uuid;index" json:"etapa_id,omitempty"`
+	Etapa         *Etapa      `gorm:"foreignKey:EtapaID" json:"etapa,omitempty"`
+	Especie       string      `gorm:"size:30;not null;index" json:"especie" binding:"required"`
+	TamanhoMinimo float64     `gorm:"type:decimal(10,2);not null" json:"tamanho_minimo" binding:"min=0"` // 0 = sem mínimo
+	TamanhoMaximo float64     `gorm:"type:decimal(10,2);not null" json:"tamanho_maximo" binding:"min=0"` // maior medida computada; 0 = sem limite
+	EntraNaCota   bool        `gorm:"not null" json:"entra_na_cota"`
+}
+
+// TableName especifica o nome da tabela
+func (RegraEspecie) TableName() string {
+	return "regras_especies"
+}
+
+// Escopo retorna "etapa", "modalidade" ou "edicao" conforme a chave preenchida
+func (r *RegraEspecie) Escopo() string {
+	switch {
+	case r.EtapaID != nil:
+		return "etapa"
+	case r.ModalidadeID != nil:
+		return "modalidade"
+	case r.EdicaoID != nil:
+		return "edicao"
+	}
+	return ""
+}
+
+// TamanhoComputado limita a medida ao máximo computado pela regra
+func (r *RegraEspecie) TamanhoComputado(tamanho float64) float64 {
+	if r.TamanhoMaximo > 0 {
+		return math.Min(tamanho, r.TamanhoMaximo)
+	}
+	return tamanho
+}
+
+// RegraPadrao é a regra do regulamento quando não há regra cadastrada. Só os tucunarés
+// têm mínimo e entram na cota; traíra e espécies acrescentadas ao catálogo não, até que
+// uma regra seja cadastrada para elas.
+func RegraPadrao(especie string) RegraEspecie {
+	if especie == EspecieTucunareAzul || especie == EspecieTucunareAmarelo {
+		return RegraEspecie{Especie: especie, TamanhoMinimo: TamanhoMinimoTucunare, EntraNaCota: true}
+	}
+	return RegraEspecie{Especie: especie}
+}
+
+// RegrasEspecie são as regras em vigor em uma etapa, por espécie
+type RegrasEspecie map[string]RegraEspecie
+
+// ResolverRegras escolhe, para cada espécie, a regra mais específica que se aplica à etapa
+func ResolverRegras(etapa *Etapa, regras []RegraEspecie) RegrasEspecie {
+	resolvidas := RegrasEspecie{}
+	prioridades := map[string]int{}
+
+	for _, regra := range regras {
+		prioridade := 0
+		switch {
+		case regra.EtapaID != nil && *regra.EtapaID == etapa.ID:
+			prioridade = 3
+		case regra.EtapaID == nil && regra.ModalidadeID != nil && *regra.ModalidadeID == etapa.ModalidadeID:
+			prioridade = 2
+		case regra.EtapaID == nil && regra.ModalidadeID == nil && regra.EdicaoID != nil && *regra.EdicaoID == etapa.EdicaoID:
+			prioridade = 1
+		default:
+			continue
+		}
+
+		if prioridade > prioridades[regra.Especie] {
+			resolvidas[regra.Especie] = regra
+			prioridades[regra.Especie] = prioridade
+		}
+	}
+
+	return resolvidas
+}
+
+// Para retorna a regra da espécie, ou a padrão do regulamento
+func (r RegrasEspecie) Para(especie string) RegraEspecie {
+	if regra, ok := r[especie]; ok {
+		return regra
+	}
+	return RegraPadrao(especie)
+}
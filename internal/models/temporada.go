@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ResultadoEtapa é o resultado de um competidor em uma etapa da temporada
+type ResultadoEtapa struct {
+	EtapaID        string  `json:"etapa_id"`
+	EtapaNumero    int     `json:"etapa_numero"`
+	Posicao        int     `json:"posicao"` // 0 = não participou
+	PontuacaoEtapa float64 `json:"pontuacao_etapa"`
+	Pontos         float64 `json:"pontos"`
+	Descartado     bool    `json:"descartado"`
+}
+
+// ClassificacaoTemporada é a linha de um competidor na classificação geral da edição
+type ClassificacaoTemporada struct {
+	CompetidorID      string           `json:"competidor_id"`
+	Nome              string           `json:"nome"`
+	Posicao           int              `json:"posicao"`
+	Empatado          bool             `json:"empatado"`
+	CriterioDesempate string           `json:"criterio_desempate,omitempty"`
+	Pontos            float64          `json:"pontos"`        // após os descartes
+	PontosBrutos      float64          `json:"pontos_brutos"` // soma de todas as etapas
+	Vitorias          int              `json:"vitorias"`
+	PontuacaoEtapas   float64          `json:"pontuacao_etapas"` // soma das pontuações nas etapas
+	Resultados        []ResultadoEtapa `json:"resultados"`
+}
+
+// TabelaPontosTemporada retorna a tabela de pontos por colocação da edição, ou a padrão
+func (e *Edicao) TabelaPontosTemporada() ([]float64, error) {
+	if strings.TrimSpace(e.TabelaPontos) == "" {
+		return TabelaPontosPadrao(), nil
+	}
+
+	var tabela []float64
+	for _, valor := range strings.Split(e.TabelaPontos, ",") {
+		pontos, err := strconv.ParseFloat(strings.TrimSpace(valor), 64)
+		if err != nil || pontos < 0 {
+			return nil, fmt.Errorf("tabela de pontos inválida: %q", valor)
+		}
+		tabela = append(tabela, pontos)
+	}
+	return tabela, nil
+}
+
+// CriteriosDesempateTemporada retorna a lista de desempate da temporada, ou a padrão
+func (e *Edicao) CriteriosDesempateTemporada() []string {
+	var criterios []string
+	for _, criterio := range strings.Split(e.DesempateTemporada, ",") {
+		if criterio = strings.TrimSpace(criterio); criterio != "" {
+			criterios = append(criterios, criterio)
+		}
+	}
+
+	if len(criterios) == 0 {
+		return DesempateTemporadaPadrao()
+	}
+	return criterios
+}
+
+// ValidarDesempateTemporada confere se os critérios existem e não se repetem
+func ValidarDesempateTemporada(desempate string) error {
+	vistos := map[string]bool{}
+	for _, criterio := range strings.Split(desempate, ",") {
+		criterio = strings.TrimSpace(criterio)
+		if criterio == "" {
+			continue
+		}
+		if !ValidarCriterioDesempateTemporada(criterio) {
+			return fmt.Errorf("critério de desempate da temporada inválido: %s", criterio)
+		}
+		if vistos[criterio] {
+			return fmt.Errorf("critério de desempate repetido: %s", criterio)
+		}
+		vistos[criterio] = true
+	}
+	return nil
+}
+
+// PontosPorPosicao retorna os pontos da colocação; fora da tabela ou sem participação vale 0
+func PontosPorPosicao(tabela []float64, posicao int) float64 {
+	if posicao < 1 || posicao > len(tabela) {
+		return 0
+	}
+	return tabela[posicao-1]
+}
+
+// Totalizar soma os pontos das etapas descartando os piores resultados.
+// Etapas sem participação contam como 0 e são as primeiras a serem descartadas.
+func (c *ClassificacaoTemporada) Totalizar(descartar int) {
+	c.PontosBrutos, c.Pontos, c.Vitorias, c.PontuacaoEtapas = 0, 0, 0, 0
+
+	for i := range c.Resultados {
+		resultado := &c.Resultados[i]
+		resultado.Descartado = false
+		c.PontosBrutos += resultado.Pontos
+		c.PontuacaoEtapas += resultado.PontuacaoEtapa
+		if resultado.Posicao == 1 {
+			c.Vitorias++
+		}
+	}
+
+	// Piores primeiro; no empate descarta a etapa mais antiga
+	piores := make([]*ResultadoEtapa, len(c.Resultados))
+	for i := range c.Resultados {
+		piores[i] = &c.Resultados[i]
+	}
+	sort.SliceStable(piores, func(a, b int) bool {
+		if piores[a].Pontos != piores[b].Pontos {
+			return piores[a].Pontos < piores[b].Pontos
+		}
+		return piores[a].EtapaNumero < piores[b].EtapaNumero
+	})
+
+	for i := 0; i < descartar && i < len(piores); i++ {
+		piores[i].Descartado = true
+	}
+
+	for _, resultado := range c.Resultados {
+		if !resultado.Descartado {
+			c.Pontos += resultado.Pontos
+		}
+	}
+}
+
+// ClassificarTemporada ordena pelos pontos e aplica os critérios de desempate da temporada.
+// Quem continuar empatado em todos os critérios divide a posição.
+func ClassificarTemporada(classificacao []ClassificacaoTemporada, criterios []string) {
+	sort.SliceStable(classificacao, func(a, b int) bool {
+		if classificacao[a].Pontos != classificacao[b].Pontos {
+			return classificacao[a].Pontos > classificacao[b].Pontos
+		}
+		_, aVence := desempatarTemporada(&classificacao[a], &classificacao[b], criterios)
+		return aVence
+	})
+
+	for i := range classificacao {
+		atual := &classificacao[i]
+		atual.Posicao = i + 1
+		atual.CriterioDesempate = ""
+		atual.Empatado = false
+
+		if i == 0 {
+			continue
+		}
+
+		anterior := &classificacao[i-1]
+		if anterior.Pontos != atual.Pontos {
+			continue
+		}
+
+		criterio, _ := desempatarTemporada(anterior, atual, criterios)
+		if criterio == "" {
+			atual.Posicao = anterior.Posicao
+			atual.Empatado = true
+			anterior.Empatado = true
+			continue
+		}
+		atual.CriterioDesempate = criterio
+	}
+}
+
+// desempatarTemporada retorna o primeiro critério que diferencia a de b e se a fica à frente
+func desempatarTemporada(a, b *ClassificacaoTemporada, criterios []string) (string, bool) {
+	for _, criterio := range criterios {
+		switch criterio {
+		case DesempateVitorias:
+			if a.Vitorias != b.Vitorias {
+				return criterio, a.Vitorias > b.Vitorias
+			}
+		case DesempatePontosBrutos:
+			if a.PontosBrutos != b.PontosBrutos {
+				return criterio, a.PontosBrutos > b.PontosBrutos
+			}
+		case DesempatePontuacaoEtapas:
+			if a.PontuacaoEtapas != b.PontuacaoEtapas {
+				return criterio, a.PontuacaoEtapas > b.PontuacaoEtapas
+			}
+		case DesempateUltimaEtapa:
+			// Melhor colocação na etapa mais recente em que os dois se diferenciam
+			for i := len(a.Resultados) - 1; i >= 0 && i < len(b.Resultados); i-- {
+				pa, pb := a.Resultados[i].Posicao, b.Resultados[i].Posicao
+				if pa != pb {
+					return criterio, pb == 0 || (pa != 0 && pa < pb)
+				}
+			}
+		}
+	}
+	return "", false
+}
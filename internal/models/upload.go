@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Upload representa um envio resumível (protocolo tus) do vídeo de uma captura
+type Upload struct {
+	BaseModel
+	CapturaID   string    `gorm:"type:uuid;not null;index" json:"captura_id"`
+	Captura     *Captura  `gorm:"foreignKey:CapturaID" json:"captura,omitempty"`
+	EnviadoPor  string    `gorm:"type:uuid" json:"enviado_por"`
+	NomeArquivo string    `gorm:"size:255" json:"nome_arquivo"`
+	Tamanho     int64     `gorm:"not null" json:"tamanho"`
+	Recebido    int64     `gorm:"default:0" json:"recebido"` // Upload-Offset atual
+	Partes      string    `gorm:"type:text" json:"-"`        // chaves das partes no storage, uma por linha
+	Concluido   bool      `gorm:"default:false;index" json:"concluido"`
+	ExpiraEm    time.Time `gorm:"not null;index" json:"expira_em"`
+}
+
+// TableName especifica o nome da tabela
+func (Upload) TableName() string {
+	return "uploads"
+}
+
+// ChavesPartes retorna as chaves das partes recebidas, na ordem
+func (u *Upload) ChavesPartes() []string {
+	return strings.Fields(u.Partes)
+}
+
+// NovaChaveParte gera a chave de storage para uma parte que começa em offset.
+// O sufixo evita que dois PATCH concorrentes no mesmo offset usem o mesmo arquivo.
+func (u *Upload) NovaChaveParte(offset int64, sufixo string) string {
+	return fmt.Sprintf("uploads/%s/parte-%015d-%s", u.ID, offset, sufixo)
+}
+
+// EstaExpirado verifica se o prazo para concluir o envio acabou
+func (u *Upload) EstaExpirado() bool {
+	return !u.Concluido && time.Now().After(u.ExpiraEm)
+}
@@ -0,0 +1,257 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Dimensões da página A4 em pontos (1/72 pol.)
+const (
+	LarguraA4 = 595.28
+	AlturaA4  = 841.89
+)
+
+// Alinhamento do texto dentro de uma largura
+type Alinhamento int
+
+const (
+	Esquerda Alinhamento = iota
+	Centro
+	Direita
+)
+
+// Documento monta um PDF com as fontes padrão Helvetica e Helvetica-Bold, que todo leitor
+// de PDF já tem: não embute fontes nem depende de programas externos.
+type Documento struct {
+	paginas  []pagina
+	atual    *bytes.Buffer
+	largura  float64
+	altura   float64
+	winAnsi  *encoding.Encoder
+	Titulo   string // metadado /Title
+	Produtor string // metadado /Producer
+}
+
+// pagina guarda o conteúdo e o tamanho de cada página, que pode mudar ao longo do documento
+type pagina struct {
+	conteudo *bytes.Buffer
+	largura  float64
+	altura   float64
+}
+
+// Novo cria um documento A4 retrato, sem páginas
+func Novo() *Documento {
+	return &Documento{
+		largura: LarguraA4,
+		altura:  AlturaA4,
+		winAnsi: encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder()),
+	}
+}
+
+// Paisagem troca a orientação das próximas páginas para A4 deitado; as já criadas não mudam
+func (d *Documento) Paisagem() {
+	d.largura, d.altura = AlturaA4, LarguraA4
+}
+
+// Largura retorna a largura da página atual
+func (d *Documento) Largura() float64 {
+	return d.largura
+}
+
+// Altura retorna a altura da página atual
+func (d *Documento) Altura() float64 {
+	return d.altura
+}
+
+// NovaPagina começa uma página em branco; as coordenadas partem do canto inferior esquerdo
+func (d *Documento) NovaPagina() {
+	d.atual = &bytes.Buffer{}
+	d.paginas = append(d.paginas, pagina{conteudo: d.atual, largura: d.largura, altura: d.altura})
+}
+
+// Paginas retorna quantas páginas o documento tem
+func (d *Documento) Paginas() int {
+	return len(d.paginas)
+}
+
+// Texto escreve o texto com a base em (x, y)
+func (d *Documento) Texto(x, y, tamanho float64, negrito bool, texto string) {
+	fonte := "F1"
+	if negrito {
+		fonte = "F2"
+	}
+	fmt.Fprintf(d.atual, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fonte, tamanho, x, y, d.escapar(texto))
+}
+
+// TextoAlinhado escreve o texto dentro da faixa [x, x+largura], cortando o que não couber
+func (d *Documento) TextoAlinhado(x, y, largura, tamanho float64, negrito bool, alinhamento Alinhamento, texto string) {
+	texto = Cortar(texto, largura, tamanho, negrito)
+	ocupado := LarguraTexto(texto, tamanho, negrito)
+
+	switch alinhamento {
+	case Centro:
+		x += (largura - ocupado) / 2
+	case Direita:
+		x += largura - ocupado
+	}
+	d.Texto(x, y, tamanho, negrito, texto)
+}
+
+// CorTexto define o tom de cinza do texto e dos preenchimentos seguintes (0 = preto, 1 = branco)
+func (d *Documento) CorTexto(cinza float64) {
+	fmt.Fprintf(d.atual, "%.2f g\n", cinza)
+}
+
+// Linha traça uma linha de (x1, y1) a (x2, y2) na espessura e tom de cinza (0 = preto, 1 = branco)
+func (d *Documento) Linha(x1, y1, x2, y2, espessura, cinza float64) {
+	fmt.Fprintf(d.atual, "%.2f G %.2f w %.2f %.2f m %.2f %.2f l S\n", cinza, espessura, x1, y1, x2, y2)
+}
+
+// Retangulo preenche um retângulo no tom de cinza informado
+func (d *Documento) Retangulo(x, y, largura, altura, cinza float64) {
+	fmt.Fprintf(d.atual, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", cinza, x, y, largura, altura)
+}
+
+// Bytes serializa o documento
+func (d *Documento) Bytes() ([]byte, error) {
+	if len(d.paginas) == 0 {
+		d.NovaPagina()
+	}
+
+	var saida bytes.Buffer
+	var offsets []int
+
+	objeto := func(conteudo string) {
+		offsets = append(offsets, saida.Len())
+		fmt.Fprintf(&saida, "%d 0 obj\n%s\nendobj\n", len(offsets), conteudo)
+	}
+
+	saida.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catálogo, 2: árvore de páginas, 3 e 4: fontes, 5: informações; depois página e conteúdo
+	kids := make([]string, len(d.paginas))
+	for i := range d.paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+
+	objeto("<< /Type /Catalog /Pages 2 0 R /Lang (pt-BR) >>")
+	objeto(fmt.Sprintf("<< /Type /Pages /Count %d /Kids [%s] >>", len(d.paginas), strings.Join(kids, " ")))
+	objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	objeto(fmt.Sprintf("<< /Title (%s) /Producer (%s) >>", d.escapar(d.Titulo), d.escapar(d.Produtor)))
+
+	for i, pagina := range d.paginas {
+		var comprimido bytes.Buffer
+		z := zlib.NewWriter(&comprimido)
+		if _, err := z.Write(pagina.conteudo.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := z.Close(); err != nil {
+			return nil, err
+		}
+
+		objeto(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pagina.largura, pagina.altura, 7+i*2))
+		objeto(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			comprimido.Len(), comprimido.Bytes()))
+	}
+
+	xref := saida.Len()
+	fmt.Fprintf(&saida, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&saida, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&saida, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return saida.Bytes(), nil
+}
+
+// escapar converte para WinAnsi (o que não existir vira "?") e protege os caracteres especiais
+func (d *Documento) escapar(texto string) string {
+	convertido, err := d.winAnsi.String(texto)
+	if err != nil {
+		convertido = texto
+	}
+
+	substituir := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", "", "\n", " ")
+	return substituir.Replace(convertido)
+}
+
+// LarguraTexto estima a largura do texto em pontos pelas métricas da Helvetica
+func LarguraTexto(texto string, tamanho float64, negrito bool) float64 {
+	metricas := &larguraHelvetica
+	if negrito {
+		metricas = &larguraHelveticaBold
+	}
+
+	total := 0
+	for _, r := range texto {
+		total += larguraCaractere(metricas, r)
+	}
+	return float64(total) * tamanho / 1000
+}
+
+// Cortar encurta o texto com reticências até caber na largura
+func Cortar(texto string, largura, tamanho float64, negrito bool) string {
+	if LarguraTexto(texto, tamanho, negrito) <= largura {
+		return texto
+	}
+
+	for len(texto) > 0 {
+		_, n := utf8.DecodeLastRuneInString(texto)
+		texto = texto[:len(texto)-n]
+		if LarguraTexto(texto+"...", tamanho, negrito) <= largura {
+			return strings.TrimSpace(texto) + "..."
+		}
+	}
+	return ""
+}
+
+// larguraCaractere retorna a largura (em milésimos do corpo) de um caractere;
+// letras acentuadas usam a largura da letra base
+func larguraCaractere(metricas *[95]int, r rune) int {
+	if r >= 32 && r <= 126 {
+		return metricas[r-32]
+	}
+
+	switch r {
+	case 'º', 'ª':
+		return 365
+	case '°':
+		return 400
+	case '–':
+		return 556
+	}
+
+	if base := []rune(norm.NFD.String(string(r))); len(base) > 0 && base[0] >= 32 && base[0] <= 126 {
+		return metricas[base[0]-32]
+	}
+	return 556
+}
+
+// Larguras dos caracteres ASCII 32..126 (AFM padrão Adobe)
+var larguraHelvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var larguraHelveticaBold = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
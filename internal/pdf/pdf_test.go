@@ -0,0 +1,125 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lerXref confere o startxref e a tabela xref e retorna os offsets dos objetos 1..n
+func lerXref(t *testing.T, arquivo []byte) []int {
+	t.Helper()
+
+	fim := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(arquivo)
+	if fim == nil {
+		t.Fatal("startxref ausente no fim do arquivo")
+	}
+	inicio, _ := strconv.Atoi(string(fim[1]))
+	if inicio >= len(arquivo) || !bytes.HasPrefix(arquivo[inicio:], []byte("xref\n")) {
+		t.Fatalf("startxref %d não aponta para a tabela xref", inicio)
+	}
+
+	linhas := strings.Split(string(arquivo[inicio:]), "\n")
+	var primeiro, total int
+	if _, err := fmt.Sscanf(linhas[1], "%d %d", &primeiro, &total); err != nil || primeiro != 0 {
+		t.Fatalf("subseção da xref inválida: %q", linhas[1])
+	}
+	if linhas[2] != "0000000000 65535 f " {
+		t.Fatalf("entrada livre inválida: %q", linhas[2])
+	}
+
+	offsets := make([]int, total-1)
+	for i := range offsets {
+		entrada := linhas[3+i]
+		if len(entrada) != 19 || !strings.HasSuffix(entrada, " 00000 n ") {
+			t.Fatalf("entrada %d da xref fora do formato de 20 bytes: %q", i+1, entrada)
+		}
+		offsets[i], _ = strconv.Atoi(entrada[:10])
+	}
+	if linhas[3+len(offsets)] != "trailer" || !strings.Contains(linhas[4+len(offsets)], fmt.Sprintf("/Size %d ", total)) {
+		t.Fatalf("trailer não confere com a xref: %q", linhas[3+len(offsets):5+len(offsets)])
+	}
+	return offsets
+}
+
+func TestBytesXref(t *testing.T) {
+	casos := []struct {
+		nome    string
+		montar  func(d *Documento)
+		paginas int
+	}{
+		{nome: "documento sem páginas", montar: func(d *Documento) {}, paginas: 1},
+		{
+			nome: "texto com acentos e parênteses",
+			montar: func(d *Documento) {
+				d.Titulo = "Ranking (provisório)"
+				d.NovaPagina()
+				d.Texto(40, 800, 12, true, "Classificação: São Félix do Araguaia (MT) \\ fim")
+				d.Linha(40, 790, 555, 790, 0.5, 0)
+			},
+			paginas: 1,
+		},
+		{
+			nome: "várias páginas",
+			montar: func(d *Documento) {
+				for i := 0; i < 5; i++ {
+					d.NovaPagina()
+					d.Retangulo(40, 40, 100, 20, 0.9)
+					d.TextoAlinhado(40, 45, 100, 10, false, Centro, fmt.Sprintf("Página %d", i+1))
+				}
+			},
+			paginas: 5,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			d := Novo()
+			caso.montar(d)
+			arquivo, err := d.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			offsets := lerXref(t, arquivo)
+			if len(offsets) != 5+2*caso.paginas {
+				t.Fatalf("esperados %d objetos, a xref tem %d", 5+2*caso.paginas, len(offsets))
+			}
+			for i, offset := range offsets {
+				cabecalho := fmt.Sprintf("%d 0 obj\n", i+1)
+				if offset >= len(arquivo) || !bytes.HasPrefix(arquivo[offset:], []byte(cabecalho)) {
+					t.Errorf("offset %d da xref não aponta para %q", offset, cabecalho)
+				}
+			}
+		})
+	}
+}
+
+func TestPaisagemSoNasProximasPaginas(t *testing.T) {
+	d := Novo()
+	d.NovaPagina()
+	d.Paisagem()
+	d.NovaPagina()
+	if d.Largura() != AlturaA4 || d.Altura() != LarguraA4 {
+		t.Errorf("página atual deveria estar deitada, está %.2fx%.2f", d.Largura(), d.Altura())
+	}
+
+	arquivo, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caixas := regexp.MustCompile(`/MediaBox \[0 0 ([\d.]+) ([\d.]+)\]`).FindAllStringSubmatch(string(arquivo), -1)
+	esperadas := [][2]string{{"595.28", "841.89"}, {"841.89", "595.28"}}
+	if len(caixas) != len(esperadas) {
+		t.Fatalf("esperadas %d páginas, encontradas %d", len(esperadas), len(caixas))
+	}
+	for i, caixa := range caixas {
+		if caixa[1] != esperadas[i][0] || caixa[2] != esperadas[i][1] {
+			t.Errorf("página %d com MediaBox %sx%s, esperado %sx%s", i+1, caixa[1], caixa[2], esperadas[i][0], esperadas[i][1])
+		}
+	}
+}
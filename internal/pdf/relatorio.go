@@ -0,0 +1,191 @@
+package pdf
+
+import (
+	"fmt"
+	"time"
+)
+
+// Margens e medidas do relatório, em pontos
+const (
+	margem         = 36.0
+	alturaLinha    = 16.0
+	alturaCabecalh = 18.0
+	alturaRodape   = 40.0
+	corpoTexto     = 8.5
+)
+
+// Coluna de uma tabela do relatório. Larguras são proporcionais: a tabela ocupa a página toda.
+type Coluna struct {
+	Titulo      string
+	Peso        float64
+	Alinhamento Alinhamento
+}
+
+// Secao é um bloco do relatório com título e tabela (ex.: uma modalidade da temporada)
+type Secao struct {
+	Titulo   string
+	Colunas  []Coluna
+	Linhas   [][]string
+	Destaque []bool // linhas em negrito (ex.: premiados); pode ser menor que Linhas
+}
+
+// Relatorio é uma classificação pronta para imprimir, no formato das planilhas publicadas pela organização
+type Relatorio struct {
+	Titulo     string   // ex.: CLASSIFICAÇÃO GERAL
+	Subtitulos []string // edição, etapa, categoria, versão...
+	Rodape     string   // patrocinadores
+	Paisagem   bool
+	Secoes     []Secao
+	GeradoEm   time.Time
+}
+
+// Gerar desenha o relatório, quebrando páginas e repetindo o cabeçalho da tabela em cada uma
+func (r *Relatorio) Gerar() ([]byte, error) {
+	doc := Novo()
+	doc.Titulo = r.Titulo
+	doc.Produtor = "Copa Trick Fish"
+	if r.Paisagem {
+		doc.Paisagem()
+	}
+	if r.GeradoEm.IsZero() {
+		r.GeradoEm = time.Now()
+	}
+
+	desenho := &desenho{doc: doc, relatorio: r}
+	desenho.novaPagina(true)
+
+	for _, secao := range r.Secoes {
+		desenho.secao(secao)
+	}
+
+	desenho.rodape()
+	return doc.Bytes()
+}
+
+// desenho guarda a posição corrente enquanto o relatório é desenhado
+type desenho struct {
+	doc       *Documento
+	relatorio *Relatorio
+	y         float64
+}
+
+// novaPagina fecha a página atual com o rodapé e abre outra; o título completo só na primeira
+func (d *desenho) novaPagina(primeira bool) {
+	if !primeira {
+		d.rodape()
+	}
+	d.doc.NovaPagina()
+
+	largura := d.doc.Largura() - 2*margem
+	d.y = d.doc.Altura() - margem
+
+	if !primeira {
+		d.y -= 10
+		d.doc.TextoAlinhado(margem, d.y, largura, 9, true, Centro, d.relatorio.Titulo+" (continuação)")
+		d.y -= 16
+		return
+	}
+
+	d.y -= 16
+	d.doc.TextoAlinhado(margem, d.y, largura, 16, true, Centro, d.relatorio.Titulo)
+	d.y -= 6
+	for _, subtitulo := range d.relatorio.Subtitulos {
+		d.y -= 14
+		d.doc.TextoAlinhado(margem, d.y, largura, 10.5, false, Centro, subtitulo)
+	}
+	d.y -= 12
+	d.doc.Linha(margem, d.y, margem+largura, d.y, 1, 0)
+	d.y -= 14
+}
+
+// secao desenha o título e a tabela de uma seção
+func (d *desenho) secao(secao Secao) {
+	largura := d.doc.Largura() - 2*margem
+	larguras := distribuir(secao.Colunas, largura)
+
+	// Título, cabeçalho e ao menos uma linha precisam caber juntos
+	if d.y-(22+alturaCabecalh+alturaLinha) < margem+alturaRodape {
+		d.novaPagina(false)
+	}
+
+	if secao.Titulo != "" {
+		d.y -= 12
+		d.doc.Texto(margem, d.y, 11.5, true, secao.Titulo)
+		d.y -= 10
+	}
+	d.cabecalho(secao.Colunas, larguras)
+
+	for i, linha := range secao.Linhas {
+		if d.y-alturaLinha < margem+alturaRodape {
+			d.novaPagina(false)
+			d.cabecalho(secao.Colunas, larguras)
+		}
+
+		negrito := i < len(secao.Destaque) && secao.Destaque[i]
+		if i%2 == 1 {
+			d.doc.Retangulo(margem, d.y-alturaLinha, largura, alturaLinha, 0.94)
+		}
+
+		x := margem
+		for j, coluna := range secao.Colunas {
+			valor := ""
+			if j < len(linha) {
+				valor = linha[j]
+			}
+			d.doc.TextoAlinhado(x+3, d.y-11.5, larguras[j]-6, corpoTexto, negrito, coluna.Alinhamento, valor)
+			x += larguras[j]
+		}
+		d.y -= alturaLinha
+	}
+
+	d.doc.Linha(margem, d.y, margem+largura, d.y, 0.5, 0.5)
+	d.y -= 14
+}
+
+// cabecalho desenha a linha de títulos da tabela
+func (d *desenho) cabecalho(colunas []Coluna, larguras []float64) {
+	largura := d.doc.Largura() - 2*margem
+
+	d.doc.Retangulo(margem, d.y-alturaCabecalh, largura, alturaCabecalh, 0.2)
+	d.doc.CorTexto(1) // texto branco sobre a faixa escura
+
+	x := margem
+	for i, coluna := range colunas {
+		d.doc.TextoAlinhado(x+3, d.y-12.5, larguras[i]-6, corpoTexto, true, coluna.Alinhamento, coluna.Titulo)
+		x += larguras[i]
+	}
+
+	d.doc.CorTexto(0)
+	d.y -= alturaCabecalh
+}
+
+// rodape escreve patrocinadores, data de emissão e número da página
+func (d *desenho) rodape() {
+	largura := d.doc.Largura() - 2*margem
+	y := margem + alturaRodape - 12
+
+	d.doc.Linha(margem, y+8, margem+largura, y+8, 0.5, 0.6)
+	if d.relatorio.Rodape != "" {
+		d.doc.TextoAlinhado(margem, y-4, largura, 9, true, Centro, d.relatorio.Rodape)
+	}
+
+	emissao := "Emitido em " + d.relatorio.GeradoEm.Format("02/01/2006 15:04")
+	d.doc.Texto(margem, margem, 7, false, emissao)
+	d.doc.TextoAlinhado(margem, margem, largura, 7, false, Direita, fmt.Sprintf("Página %d", d.doc.Paginas()))
+}
+
+// distribuir converte os pesos das colunas em larguras que somam a largura útil
+func distribuir(colunas []Coluna, largura float64) []float64 {
+	total := 0.0
+	for _, coluna := range colunas {
+		total += coluna.Peso
+	}
+
+	larguras := make([]float64, len(colunas))
+	for i, coluna := range colunas {
+		if total > 0 {
+			larguras[i] = largura * coluna.Peso / total
+		}
+	}
+	return larguras
+}
@@ -0,0 +1,238 @@
+package placar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tipos de evento enviados aos assinantes
+const (
+	EventoSnapshot = "snapshot" // placar completo
+	EventoDiff     = "diff"     // apenas as linhas que mudaram
+)
+
+const (
+	tamanhoHistorico = 256 // diffs guardados para quem reconecta com Last-Event-ID
+	tamanhoFila      = 32  // eventos pendentes por assinante antes de derrubá-lo
+)
+
+// Linha é a posição de um competidor no placar provisório da etapa
+type Linha struct {
+	InscricaoID      string  `json:"inscricao_id"`
+	Competidor       string  `json:"competidor"`
+	Posicao          int     `json:"posicao"`
+	Empatado         bool    `json:"empatado"`
+	PontuacaoTotal   float64 `json:"pontuacao_total"`
+	MaiorPeixe       float64 `json:"maior_peixe"`
+	QuantidadePeixes int     `json:"quantidade_peixes"`
+	Unidade          string  `json:"unidade"`
+}
+
+// Diferenca são as mudanças de um placar para o seguinte
+type Diferenca struct {
+	Alteradas []Linha  `json:"alteradas"`
+	Removidas []string `json:"removidas"` // inscrições que saíram do placar
+}
+
+// Evento é uma mensagem do placar; o ID é o Last-Event-ID que o cliente devolve ao reconectar
+type Evento struct {
+	ID    string
+	Tipo  string
+	Dados interface{} // []Linha no snapshot, Diferenca no diff
+}
+
+// quadro guarda o placar atual de uma etapa, os últimos diffs e os assinantes
+type quadro struct {
+	geracao    string // muda quando o quadro é recriado, invalidando IDs antigos
+	seq        uint64
+	linhas     map[string]Linha
+	historico  []Evento
+	assinantes map[chan Evento]struct{}
+}
+
+var (
+	mu      sync.Mutex
+	quadros = map[string]*quadro{}
+)
+
+// Acompanhada indica se a etapa tem placar em memória com alguém assinando
+func Acompanhada(etapaID string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	q, ok := quadros[etapaID]
+	return ok && len(q.assinantes) > 0
+}
+
+// Publicar troca o placar da etapa e envia aos assinantes apenas o que mudou.
+// Etapas sem assinantes não têm quadro e a publicação é descartada.
+func Publicar(etapaID string, linhas []Linha) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	q, ok := quadros[etapaID]
+	if !ok {
+		return
+	}
+
+	diferenca := comparar(q.linhas, linhas)
+	if len(diferenca.Alteradas) == 0 && len(diferenca.Removidas) == 0 {
+		return
+	}
+
+	q.linhas = make(map[string]Linha, len(linhas))
+	for _, linha := range linhas {
+		q.linhas[linha.InscricaoID] = linha
+	}
+
+	q.seq++
+	evento := Evento{ID: q.id(q.seq), Tipo: EventoDiff, Dados: diferenca}
+
+	q.historico = append(q.historico, evento)
+	if len(q.historico) > tamanhoHistorico {
+		q.historico = q.historico[len(q.historico)-tamanhoHistorico:]
+	}
+
+	for canal := range q.assinantes {
+		select {
+		case canal <- evento:
+		default:
+			// Assinante lento: derruba para que reconecte com Last-Event-ID
+			delete(q.assinantes, canal)
+			close(canal)
+		}
+	}
+
+	if len(q.assinantes) == 0 {
+		delete(quadros, etapaID)
+	}
+}
+
+// Assinar registra um assinante da etapa. Se ninguém acompanhava a etapa, o quadro é criado
+// com as linhas informadas; senão elas são ignoradas. Retorna os eventos a enviar primeiro:
+// os diffs depois de ultimoID, se ainda estão no histórico, ou um snapshot do placar atual.
+// A função devolvida cancela a assinatura; o último a sair descarta o quadro da etapa.
+func Assinar(etapaID, ultimoID string, linhas []Linha) ([]Evento, <-chan Evento, func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	q := obterQuadro(etapaID, linhas)
+
+	iniciais, ok := q.desde(ultimoID)
+	if !ok {
+		iniciais = []Evento{{ID: q.id(q.seq), Tipo: EventoSnapshot, Dados: q.ordenado()}}
+	}
+
+	canal := make(chan Evento, tamanhoFila)
+	q.assinantes[canal] = struct{}{}
+
+	cancelar := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if _, ok := q.assinantes[canal]; ok {
+			delete(q.assinantes, canal)
+			close(canal)
+		}
+
+		// Sem assinantes o placar deixa de ser atualizado: descarta para não servir dados velhos
+		if len(q.assinantes) == 0 && quadros[etapaID] == q {
+			delete(quadros, etapaID)
+		}
+	}
+
+	return iniciais, canal, cancelar
+}
+
+// obterQuadro retorna o quadro da etapa, criando com as linhas se ainda não existe (chamar com mu travado)
+func obterQuadro(etapaID string, linhas []Linha) *quadro {
+	if q, ok := quadros[etapaID]; ok {
+		return q
+	}
+
+	q := &quadro{
+		geracao:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		linhas:     make(map[string]Linha, len(linhas)),
+		assinantes: map[chan Evento]struct{}{},
+	}
+	for _, linha := range linhas {
+		q.linhas[linha.InscricaoID] = linha
+	}
+	quadros[etapaID] = q
+	return q
+}
+
+// id monta o ID do evento com a geração do quadro
+func (q *quadro) id(seq uint64) string {
+	return fmt.Sprintf("%s-%d", q.geracao, seq)
+}
+
+// desde retorna os diffs posteriores a ultimoID; false se o ID é de outra geração
+// ou já saiu do histórico, e o cliente precisa de um snapshot
+func (q *quadro) desde(ultimoID string) ([]Evento, bool) {
+	geracao, numero, ok := strings.Cut(ultimoID, "-")
+	if !ok || geracao != q.geracao {
+		return nil, false
+	}
+
+	seq, err := strconv.ParseUint(numero, 10, 64)
+	if err != nil || seq > q.seq {
+		return nil, false
+	}
+
+	pendentes := q.seq - seq
+	if pendentes > uint64(len(q.historico)) {
+		return nil, false
+	}
+
+	eventos := make([]Evento, pendentes)
+	copy(eventos, q.historico[uint64(len(q.historico))-pendentes:])
+	return eventos, true
+}
+
+// ordenado retorna o placar atual por posição
+func (q *quadro) ordenado() []Linha {
+	linhas := make([]Linha, 0, len(q.linhas))
+	for _, linha := range q.linhas {
+		linhas = append(linhas, linha)
+	}
+	ordenar(linhas)
+	return linhas
+}
+
+// comparar retorna as linhas novas ou alteradas e as inscrições que saíram
+func comparar(anterior map[string]Linha, atual []Linha) Diferenca {
+	diferenca := Diferenca{Alteradas: []Linha{}, Removidas: []string{}}
+
+	presentes := make(map[string]bool, len(atual))
+	for _, linha := range atual {
+		presentes[linha.InscricaoID] = true
+		if antiga, ok := anterior[linha.InscricaoID]; !ok || antiga != linha {
+			diferenca.Alteradas = append(diferenca.Alteradas, linha)
+		}
+	}
+
+	for inscricaoID := range anterior {
+		if !presentes[inscricaoID] {
+			diferenca.Removidas = append(diferenca.Removidas, inscricaoID)
+		}
+	}
+
+	ordenar(diferenca.Alteradas)
+	sort.Strings(diferenca.Removidas)
+	return diferenca
+}
+
+// ordenar põe as linhas por posição e, no empate, pela inscrição
+func ordenar(linhas []Linha) {
+	sort.Slice(linhas, func(a, b int) bool {
+		if linhas[a].Posicao != linhas[b].Posicao {
+			return linhas[a].Posicao < linhas[b].Posicao
+		}
+		return linhas[a].InscricaoID < linhas[b].InscricaoID
+	})
+}
@@ -0,0 +1,212 @@
+package placar
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// limparQuadros isola o estado global do pacote entre os testes
+func limparQuadros(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	quadros = map[string]*quadro{}
+	mu.Unlock()
+}
+
+func linha(id string, posicao int, pontos float64) Linha {
+	return Linha{InscricaoID: id, Competidor: "Competidor " + id, Posicao: posicao, PontuacaoTotal: pontos, Unidade: "cm"}
+}
+
+func TestComparar(t *testing.T) {
+	anterior := map[string]Linha{
+		"a": linha("a", 1, 90),
+		"b": linha("b", 2, 80),
+		"c": linha("c", 3, 70),
+	}
+
+	casos := []struct {
+		nome      string
+		atual     []Linha
+		alteradas []Linha
+		removidas []string
+	}{
+		{
+			nome:      "nada mudou",
+			atual:     []Linha{linha("a", 1, 90), linha("b", 2, 80), linha("c", 3, 70)},
+			alteradas: []Linha{},
+			removidas: []string{},
+		},
+		{
+			nome:      "troca de posições",
+			atual:     []Linha{linha("b", 1, 95), linha("a", 2, 90), linha("c", 3, 70)},
+			alteradas: []Linha{linha("b", 1, 95), linha("a", 2, 90)},
+			removidas: []string{},
+		},
+		{
+			nome:      "entra competidor novo",
+			atual:     []Linha{linha("a", 1, 90), linha("b", 2, 80), linha("c", 3, 70), linha("d", 4, 10)},
+			alteradas: []Linha{linha("d", 4, 10)},
+			removidas: []string{},
+		},
+		{
+			nome:      "inscrições que saíram do placar",
+			atual:     []Linha{linha("b", 1, 80)},
+			alteradas: []Linha{linha("b", 1, 80)},
+			removidas: []string{"a", "c"},
+		},
+		{
+			nome:      "placar vazio",
+			atual:     nil,
+			alteradas: []Linha{},
+			removidas: []string{"a", "b", "c"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			diferenca := comparar(anterior, caso.atual)
+
+			if !reflect.DeepEqual(diferenca.Alteradas, caso.alteradas) {
+				t.Errorf("alteradas esperadas %+v, obtidas %+v", caso.alteradas, diferenca.Alteradas)
+			}
+			if !reflect.DeepEqual(diferenca.Removidas, caso.removidas) {
+				t.Errorf("removidas esperadas %v, obtidas %v", caso.removidas, diferenca.Removidas)
+			}
+		})
+	}
+}
+
+func TestAssinarReplay(t *testing.T) {
+	limparQuadros(t)
+
+	iniciais, _, cancelar := Assinar("etapa", "", []Linha{linha("a", 1, 50)})
+	defer cancelar()
+
+	if len(iniciais) != 1 || iniciais[0].Tipo != EventoSnapshot {
+		t.Fatalf("primeira assinatura deve receber um snapshot, recebeu %+v", iniciais)
+	}
+	if linhas := iniciais[0].Dados.([]Linha); len(linhas) != 1 || linhas[0].InscricaoID != "a" {
+		t.Fatalf("snapshot deve ter as linhas da criação, tem %+v", linhas)
+	}
+	inicio := iniciais[0].ID
+
+	Publicar("etapa", []Linha{linha("a", 1, 60)})
+	Publicar("etapa", []Linha{linha("a", 1, 60), linha("b", 2, 40)})
+
+	geracao := quadros["etapa"].geracao
+
+	casos := []struct {
+		nome     string
+		ultimoID string
+		tipos    []string
+	}{
+		{nome: "em dia", ultimoID: geracao + "-2", tipos: []string{}},
+		{nome: "perdeu um diff", ultimoID: geracao + "-1", tipos: []string{EventoDiff}},
+		{nome: "perdeu tudo desde o snapshot", ultimoID: inicio, tipos: []string{EventoDiff, EventoDiff}},
+		{nome: "geração antiga", ultimoID: "outra-1", tipos: []string{EventoSnapshot}},
+		{nome: "ID à frente do placar", ultimoID: geracao + "-9", tipos: []string{EventoSnapshot}},
+		{nome: "ID malformado", ultimoID: "lixo", tipos: []string{EventoSnapshot}},
+		{nome: "sem Last-Event-ID", ultimoID: "", tipos: []string{EventoSnapshot}},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			eventos, _, cancelarCaso := Assinar("etapa", caso.ultimoID, nil)
+			defer cancelarCaso()
+
+			tipos := []string{}
+			for _, evento := range eventos {
+				tipos = append(tipos, evento.Tipo)
+			}
+			if !reflect.DeepEqual(tipos, caso.tipos) {
+				t.Errorf("eventos esperados %v, obtidos %v", caso.tipos, tipos)
+			}
+		})
+	}
+}
+
+func TestAssinarHistoricoEstourado(t *testing.T) {
+	limparQuadros(t)
+
+	iniciais, canal, cancelar := Assinar("etapa", "", nil)
+	defer cancelar()
+
+	// Consome cada evento para o assinante não ser derrubado por lentidão
+	for i := 1; i <= tamanhoHistorico+5; i++ {
+		Publicar("etapa", []Linha{linha("a", 1, float64(i))})
+		<-canal
+	}
+
+	eventos, _, cancelarAtrasado := Assinar("etapa", iniciais[0].ID, nil)
+	defer cancelarAtrasado()
+
+	if len(eventos) != 1 || eventos[0].Tipo != EventoSnapshot {
+		t.Fatalf("diffs fora do histórico devem virar snapshot, recebeu %d eventos", len(eventos))
+	}
+	if linhas := eventos[0].Dados.([]Linha); linhas[0].PontuacaoTotal != float64(tamanhoHistorico+5) {
+		t.Errorf("snapshot desatualizado: %+v", linhas)
+	}
+
+	geracao := quadros["etapa"].geracao
+	ultimoGuardado := fmt.Sprintf("%s-%d", geracao, 5)
+	eventos, _, cancelarNoLimite := Assinar("etapa", ultimoGuardado, nil)
+	defer cancelarNoLimite()
+	if len(eventos) != tamanhoHistorico {
+		t.Errorf("replay no limite do histórico deve trazer %d diffs, trouxe %d", tamanhoHistorico, len(eventos))
+	}
+}
+
+func TestQuadroDescartadoSemAssinantes(t *testing.T) {
+	limparQuadros(t)
+
+	Publicar("etapa", []Linha{linha("a", 1, 50)})
+	if Acompanhada("etapa") || len(quadros) != 0 {
+		t.Fatal("publicar sem assinantes não deve criar quadro")
+	}
+
+	_, _, primeiro := Assinar("etapa", "", nil)
+	_, _, segundo := Assinar("etapa", "", nil)
+	if !Acompanhada("etapa") {
+		t.Fatal("etapa com assinantes deve estar acompanhada")
+	}
+
+	primeiro()
+	if !Acompanhada("etapa") {
+		t.Fatal("ainda há um assinante")
+	}
+
+	segundo()
+	segundo() // cancelar de novo não deve quebrar
+	if Acompanhada("etapa") || len(quadros) != 0 {
+		t.Fatal("o último a sair deve descartar o quadro")
+	}
+
+	// Recalculo em segundo plano que chega depois do último cancelar
+	Publicar("etapa", []Linha{linha("a", 1, 70)})
+	if len(quadros) != 0 {
+		t.Fatal("publicação atrasada não deve recriar o quadro")
+	}
+}
+
+func TestAssinanteLentoDerrubado(t *testing.T) {
+	limparQuadros(t)
+
+	_, canal, cancelar := Assinar("etapa", "", nil)
+	defer cancelar()
+
+	for i := 1; i <= tamanhoFila+1; i++ {
+		Publicar("etapa", []Linha{linha("a", 1, float64(i))})
+	}
+
+	recebidos := 0
+	for range canal {
+		recebidos++
+	}
+	if recebidos != tamanhoFila {
+		t.Errorf("assinante lento deve receber %d eventos antes de cair, recebeu %d", tamanhoFila, recebidos)
+	}
+	if len(quadros) != 0 {
+		t.Error("quadro sem assinantes depois da queda deve ser descartado")
+	}
+}
@@ -0,0 +1,147 @@
+package planilha
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de exportação aceitos em ?format
+const (
+	FormatoCSV  = "csv"
+	FormatoXLSX = "xlsx"
+)
+
+// Escritor grava uma planilha linha a linha, sem guardar as linhas em memória.
+// Os valores podem ser string, int, int64, float64, bool, time.Time, *time.Time ou nil.
+type Escritor interface {
+	Linha(valores ...any) error
+	Fechar() error
+}
+
+// ValidarFormato verifica se o formato de exportação é suportado
+func ValidarFormato(formato string) bool {
+	return formato == FormatoCSV || formato == FormatoXLSX
+}
+
+// ContentType retorna o tipo MIME do formato
+func ContentType(formato string) string {
+	if formato == FormatoXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Novo cria o escritor do formato e grava a linha de títulos. Datas saem no fuso informado.
+// A saída passa por um buffer: nada chega a w antes dos primeiros kilobytes de dados.
+func Novo(formato string, w io.Writer, aba string, colunas []string, local *time.Location) (Escritor, error) {
+	if local == nil {
+		local = time.Local
+	}
+
+	var escritor Escritor
+	var err error
+
+	switch formato {
+	case FormatoCSV:
+		escritor, err = novoCSV(w, local)
+	case FormatoXLSX:
+		escritor, err = novoXLSX(w, aba, local)
+	default:
+		return nil, fmt.Errorf("formato inválido: %s", formato)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	titulos := make([]any, len(colunas))
+	for i, coluna := range colunas {
+		titulos[i] = coluna
+	}
+	if err := escritor.Linha(titulos...); err != nil {
+		return nil, err
+	}
+	return escritor, nil
+}
+
+// escritorCSV gera CSV no padrão do Excel em português: BOM UTF-8, ";" e vírgula decimal
+type escritorCSV struct {
+	buffer *bufio.Writer
+	csv    *csv.Writer
+	local  *time.Location
+}
+
+func novoCSV(w io.Writer, local *time.Location) (*escritorCSV, error) {
+	buffer := bufio.NewWriter(w)
+	if _, err := buffer.WriteString("\ufeff"); err != nil {
+		return nil, err
+	}
+
+	escritor := &escritorCSV{buffer: buffer, csv: csv.NewWriter(buffer), local: local}
+	escritor.csv.Comma = ';'
+	return escritor, nil
+}
+
+// Linha grava uma linha da planilha
+func (e *escritorCSV) Linha(valores ...any) error {
+	campos := make([]string, len(valores))
+	for i, valor := range valores {
+		campos[i] = e.texto(valor)
+	}
+	return e.csv.Write(campos)
+}
+
+// Fechar descarrega o que falta no buffer
+func (e *escritorCSV) Fechar() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return e.buffer.Flush()
+}
+
+// texto converte o valor da célula para o CSV
+func (e *escritorCSV) texto(valor any) string {
+	switch v := valor.(type) {
+	case nil:
+		return ""
+	case string:
+		// Evita que o Excel interprete textos digitados pelos usuários como fórmulas
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+	case bool:
+		return simNao(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.In(e.local).Format("02/01/2006 15:04")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return e.texto(*v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// simNao escreve booleanos como nas planilhas da organização
+func simNao(valor bool) string {
+	if valor {
+		return "Sim"
+	}
+	return "Não"
+}
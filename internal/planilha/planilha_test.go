@@ -0,0 +1,77 @@
+package planilha
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSV(t *testing.T) {
+	local := time.FixedZone("BRT", -3*60*60)
+	data := time.Date(2026, 3, 1, 13, 5, 0, 0, time.UTC)
+
+	casos := []struct {
+		nome     string
+		valores  []any
+		esperado []string
+	}{
+		{
+			nome:     "números com vírgula decimal",
+			valores:  []any{42, int64(7), 35.5, 1234.25, 0.0},
+			esperado: []string{"42", "7", "35,5", "1234,25", "0"},
+		},
+		{
+			nome:     "ponto-e-vírgula, aspas e quebra de linha",
+			valores:  []any{"Pesca; Cia", `Zé "Tucunaré"`, "linha 1\nlinha 2"},
+			esperado: []string{"Pesca; Cia", `Zé "Tucunaré"`, "linha 1\nlinha 2"},
+		},
+		{
+			nome:     "textos que o Excel leria como fórmula",
+			valores:  []any{"=SOMA(A1:A2)", "+55 65 99999-0000", "-10", "@usuario", "a=b"},
+			esperado: []string{"'=SOMA(A1:A2)", "'+55 65 99999-0000", "'-10", "'@usuario", "a=b"},
+		},
+		{
+			nome:     "datas no fuso, booleanos e vazios",
+			valores:  []any{data, &data, (*time.Time)(nil), time.Time{}, true, false, nil},
+			esperado: []string{"01/03/2026 10:05", "01/03/2026 10:05", "", "", "Sim", "Não", ""},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var saida bytes.Buffer
+			escritor, err := Novo(FormatoCSV, &saida, "teste", []string{"Coluna"}, local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := escritor.Linha(caso.valores...); err != nil {
+				t.Fatal(err)
+			}
+			if err := escritor.Fechar(); err != nil {
+				t.Fatal(err)
+			}
+
+			conteudo, ok := strings.CutPrefix(saida.String(), "\ufeff")
+			if !ok {
+				t.Fatal("CSV sem BOM UTF-8")
+			}
+
+			leitor := csv.NewReader(strings.NewReader(conteudo))
+			leitor.Comma = ';'
+			leitor.FieldsPerRecord = -1
+			linhas, err := leitor.ReadAll()
+			if err != nil {
+				t.Fatalf("CSV gerado não é lido de volta: %v\n%s", err, conteudo)
+			}
+			if len(linhas) != 2 || !reflect.DeepEqual(linhas[0], []string{"Coluna"}) {
+				t.Fatalf("esperado título e uma linha, lido %q", linhas)
+			}
+			if !reflect.DeepEqual(linhas[1], caso.esperado) {
+				t.Errorf("esperado %q, lido %q", caso.esperado, linhas[1])
+			}
+		})
+	}
+}
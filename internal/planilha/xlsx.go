@@ -0,0 +1,204 @@
+package planilha
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Partes fixas do pacote XLSX: uma pasta de trabalho com uma aba e três estilos
+// (0 = padrão, 1 = data e hora, 2 = título em negrito)
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="dd/mm/yyyy hh:mm"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`</cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+
+	xlsxInicioAba = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`
+
+	xlsxFimAba = `</sheetData></worksheet>`
+)
+
+// origemExcel é o dia zero das datas seriais do Excel
+var origemExcel = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// escritorXLSX gera a aba diretamente no zip: cada linha vai para a saída assim que é gravada
+type escritorXLSX struct {
+	zip    *zip.Writer
+	aba    *bufio.Writer
+	local  *time.Location
+	linhas int
+}
+
+func novoXLSX(w io.Writer, aba string, local *time.Location) (*escritorXLSX, error) {
+	arquivo := zip.NewWriter(w)
+
+	partes := []struct{ nome, conteudo string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", workbookXLSX(aba)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, parte := range partes {
+		destino, err := arquivo.Create(parte.nome)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(destino, parte.conteudo); err != nil {
+			return nil, err
+		}
+	}
+
+	// A aba precisa ser a última entrada: o zip só escreve uma entrada por vez
+	destino, err := arquivo.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	escritor := &escritorXLSX{zip: arquivo, aba: bufio.NewWriter(destino), local: local}
+	if _, err := escritor.aba.WriteString(xlsxInicioAba); err != nil {
+		return nil, err
+	}
+	return escritor, nil
+}
+
+// Linha grava uma linha da planilha; a primeira (títulos) sai em negrito
+func (e *escritorXLSX) Linha(valores ...any) error {
+	e.linhas++
+	estilo := ""
+	if e.linhas == 1 {
+		estilo = ` s="2"`
+	}
+
+	var linha strings.Builder
+	linha.WriteString(`<row r="` + strconv.Itoa(e.linhas) + `">`)
+	for _, valor := range valores {
+		e.celula(&linha, valor, estilo)
+	}
+	linha.WriteString(`</row>`)
+
+	_, err := e.aba.WriteString(linha.String())
+	return err
+}
+
+// Fechar encerra a aba e o zip
+func (e *escritorXLSX) Fechar() error {
+	if _, err := e.aba.WriteString(xlsxFimAba); err != nil {
+		return err
+	}
+	if err := e.aba.Flush(); err != nil {
+		return err
+	}
+	return e.zip.Close()
+}
+
+// celula escreve o valor no tipo nativo do Excel: número, data serial ou texto
+func (e *escritorXLSX) celula(linha *strings.Builder, valor any, estilo string) {
+	switch v := valor.(type) {
+	case nil:
+		linha.WriteString(`<c/>`)
+	case int:
+		linha.WriteString(`<c` + estilo + `><v>` + strconv.Itoa(v) + `</v></c>`)
+	case int64:
+		linha.WriteString(`<c` + estilo + `><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+	case float64:
+		linha.WriteString(`<c` + estilo + `><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+	case bool:
+		e.texto(linha, simNao(v), estilo)
+	case time.Time:
+		if v.IsZero() {
+			linha.WriteString(`<c/>`)
+			return
+		}
+		linha.WriteString(`<c s="1"><v>` + strconv.FormatFloat(serialExcel(v.In(e.local)), 'f', -1, 64) + `</v></c>`)
+	case *time.Time:
+		if v == nil {
+			linha.WriteString(`<c/>`)
+			return
+		}
+		e.celula(linha, *v, estilo)
+	case string:
+		e.texto(linha, v, estilo)
+	default:
+		e.texto(linha, fmt.Sprint(v), estilo)
+	}
+}
+
+// texto escreve uma célula de texto sem tabela de strings compartilhadas
+func (e *escritorXLSX) texto(linha *strings.Builder, valor, estilo string) {
+	if valor == "" {
+		linha.WriteString(`<c/>`)
+		return
+	}
+	linha.WriteString(`<c t="inlineStr"` + estilo + `><is><t xml:space="preserve">`)
+	xml.EscapeText(linha, []byte(valor))
+	linha.WriteString(`</t></is></c>`)
+}
+
+// serialExcel converte a data e hora locais para o número de dias do Excel
+func serialExcel(t time.Time) float64 {
+	parede := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return parede.Sub(origemExcel).Hours() / 24
+}
+
+// workbookXLSX monta a pasta de trabalho com o nome da aba (até 31 caracteres, sem símbolos proibidos)
+func workbookXLSX(aba string) string {
+	aba = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, aba)
+	if runas := []rune(aba); len(runas) > 31 {
+		aba = string(runas[:31])
+	}
+	if aba == "" {
+		aba = "Planilha"
+	}
+
+	var nome strings.Builder
+	xml.EscapeText(&nome, []byte(aba))
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + nome.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotaArquivos é a rota da API que entrega os arquivos do storage local
+const RotaArquivos = "/api/arquivos/"
+
+// Local armazena os arquivos no disco, abaixo de um diretório base
+type Local struct {
+	basePath string
+	baseURL  string
+	segredo  []byte
+}
+
+// NewLocal cria o storage local, garantindo que o diretório base exista
+func NewLocal(basePath, baseURL, segredo string) (*Local, error) {
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de uploads: %w", err)
+	}
+	return &Local{
+		basePath: basePath,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		segredo:  []byte(segredo),
+	}, nil
+}
+
+// caminho converte a chave em caminho no disco, impedindo sair do diretório base
+func (l *Local) caminho(key string) (string, error) {
+	limpa := filepath.Clean("/" + key)
+	if limpa == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("chave de arquivo inválida: %q", key)
+	}
+	return filepath.Join(l.basePath, filepath.FromSlash(limpa)), nil
+}
+
+// Put grava o arquivo em um temporário e renomeia ao final (escrita atômica)
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	destino, err := l.caminho(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destino), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(destino), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), destino); err != nil {
+		return fmt.Errorf("erro ao mover arquivo: %w", err)
+	}
+
+	return nil
+}
+
+// Get abre o arquivo para leitura (o *os.File retornado também é io.Seeker)
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	origem, err := l.caminho(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(origem)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrArquivoNaoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	return f, nil
+}
+
+// Delete remove o arquivo do disco
+func (l *Local) Delete(ctx context.Context, key string) error {
+	origem, err := l.caminho(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(origem); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao remover arquivo: %w", err)
+	}
+
+	return nil
+}
+
+// SignedURL gera um link para RotaArquivos assinado com HMAC e com validade
+func (l *Local) SignedURL(ctx context.Context, key string, expira time.Duration) (string, error) {
+	if _, err := l.caminho(key); err != nil {
+		return "", err
+	}
+
+	validade := strconv.FormatInt(time.Now().Add(expira).Unix(), 10)
+	query := url.Values{
+		"expira":     {validade},
+		"assinatura": {l.assinar(key, validade)},
+	}
+
+	return l.baseURL + RotaArquivos + key + "?" + query.Encode(), nil
+}
+
+// VerificarAssinatura confere um link gerado por SignedURL
+func (l *Local) VerificarAssinatura(key, expira, assinatura string) bool {
+	validade, err := strconv.ParseInt(expira, 10, 64)
+	if err != nil || time.Now().Unix() > validade {
+		return false
+	}
+	return hmac.Equal([]byte(assinatura), []byte(l.assinar(key, expira)))
+}
+
+// assinar calcula o HMAC-SHA256 de chave + validade
+func (l *Local) assinar(key, expira string) string {
+	mac := hmac.New(sha256.New, l.segredo)
+	mac.Write([]byte(key + "\n" + expira))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// payloadNaoAssinado dispensa o hash do corpo na assinatura (aceito por S3 e MinIO)
+const payloadNaoAssinado = "UNSIGNED-PAYLOAD"
+
+// S3Config reúne os dados de acesso a um bucket S3 ou compatível
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// S3 fala a API REST do S3 com assinatura AWS Signature V4
+type S3 struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3 cria o cliente S3 validando a configuração
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY e S3_SECRET_KEY são obrigatórios para storage s3")
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT inválido: %q", cfg.Endpoint)
+	}
+
+	return &S3{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Minute},
+	}, nil
+}
+
+// Put envia o arquivo com PUT Object
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	// PUT Object exige Content-Length; tamanho desconhecido passa por um temporário
+	if size < 0 {
+		tmp, err := os.CreateTemp("", "s3-upload-*")
+		if err != nil {
+			return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		if size, err = io.Copy(tmp, r); err != nil {
+			return fmt.Errorf("erro ao gravar arquivo temporário: %w", err)
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("erro ao ler arquivo temporário: %w", err)
+		}
+		r = tmp
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objetoURL(key).String(), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.executar(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Get baixa o arquivo com GET Object
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objetoURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.executar(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Delete remove o arquivo com DELETE Object
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objetoURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.executar(req)
+	if errors.Is(err, ErrArquivoNaoEncontrado) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// SignedURL gera uma URL pré-assinada de GET (assinatura na query string)
+func (s *S3) SignedURL(ctx context.Context, key string, expira time.Duration) (string, error) {
+	return s.urlAssinada(key, expira, time.Now().UTC()), nil
+}
+
+// urlAssinada monta a URL pré-assinada para o instante informado
+func (s *S3) urlAssinada(key string, expira time.Duration, agora time.Time) string {
+	u := s.objetoURL(key)
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.cfg.AccessKey + "/" + s.escopo(agora)},
+		"X-Amz-Date":          {agora.Format("20060102T150405Z")},
+		"X-Amz-Expires":       {strconv.Itoa(int(expira.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u.RawQuery = queryCanonica(query)
+
+	canonica := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		payloadNaoAssinado,
+	}, "\n")
+
+	u.RawQuery += "&X-Amz-Signature=" + s.assinar(agora, canonica)
+	return u.String()
+}
+
+// executar assina a requisição, envia e converte respostas de erro
+func (s *S3) executar(req *http.Request) (*http.Response, error) {
+	s.assinarRequisicao(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro de comunicação com o S3: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrArquivoNaoEncontrado
+	}
+
+	if resp.StatusCode >= 300 {
+		corpo, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(corpo)))
+	}
+
+	return resp, nil
+}
+
+// objetoURL monta a URL do objeto em estilo path ou virtual-host
+func (s *S3) objetoURL(key string) *url.URL {
+	u := *s.endpoint
+	caminho := strings.TrimSuffix(u.Path, "/")
+
+	if s.cfg.PathStyle {
+		caminho += "/" + s.cfg.Bucket
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+
+	u.Path = caminho + "/" + key
+	u.RawPath = caminho + "/" + escaparCaminho(key)
+	return &u
+}
+
+// assinarRequisicao adiciona o cabeçalho Authorization (Signature V4)
+func (s *S3) assinarRequisicao(req *http.Request, agora time.Time) {
+	req.Header.Set("X-Amz-Date", agora.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadNaoAssinado)
+
+	cabecalhos := map[string]string{"host": req.URL.Host}
+	for nome := range req.Header {
+		cabecalhos[strings.ToLower(nome)] = strings.TrimSpace(req.Header.Get(nome))
+	}
+	if req.ContentLength > 0 {
+		cabecalhos["content-length"] = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	nomes := make([]string, 0, len(cabecalhos))
+	for nome := range cabecalhos {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+
+	var canonicos strings.Builder
+	for _, nome := range nomes {
+		canonicos.WriteString(nome + ":" + cabecalhos[nome] + "\n")
+	}
+	assinados := strings.Join(nomes, ";")
+
+	canonica := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		queryCanonica(req.URL.Query()),
+		canonicos.String(),
+		assinados,
+		payloadNaoAssinado,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, s.escopo(agora), assinados, s.assinar(agora, canonica),
+	))
+}
+
+// escopo retorna data/região/s3/aws4_request
+func (s *S3) escopo(agora time.Time) string {
+	return agora.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// assinar calcula a assinatura V4 de uma requisição canônica
+func (s *S3) assinar(agora time.Time, canonica string) string {
+	hashCanonica := sha256.Sum256([]byte(canonica))
+	textoAssinado := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		agora.Format("20060102T150405Z"),
+		s.escopo(agora),
+		hex.EncodeToString(hashCanonica[:]),
+	}, "\n")
+
+	chave := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), agora.Format("20060102"))
+	chave = hmacSHA256(chave, s.cfg.Region)
+	chave = hmacSHA256(chave, "s3")
+	chave = hmacSHA256(chave, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(chave, textoAssinado))
+}
+
+// hmacSHA256 calcula HMAC-SHA256(chave, dado)
+func hmacSHA256(chave []byte, dado string) []byte {
+	mac := hmac.New(sha256.New, chave)
+	mac.Write([]byte(dado))
+	return mac.Sum(nil)
+}
+
+// queryCanonica ordena e codifica a query string como exige a Signature V4
+func queryCanonica(valores url.Values) string {
+	chaves := make([]string, 0, len(valores))
+	for chave := range valores {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+
+	var partes []string
+	for _, chave := range chaves {
+		lista := append([]string(nil), valores[chave]...)
+		sort.Strings(lista)
+		for _, valor := range lista {
+			partes = append(partes, escaparURI(chave)+"="+escaparURI(valor))
+		}
+	}
+
+	return strings.Join(partes, "&")
+}
+
+// escaparCaminho codifica cada segmento da chave preservando as barras
+func escaparCaminho(key string) string {
+	segmentos := strings.Split(key, "/")
+	for i, segmento := range segmentos {
+		segmentos[i] = escaparURI(segmento)
+	}
+	return strings.Join(segmentos, "/")
+}
+
+// escaparURI codifica tudo exceto os caracteres não reservados (RFC 3986)
+func escaparURI(valor string) string {
+	var b strings.Builder
+	for i := 0; i < len(valor); i++ {
+		ch := valor[i]
+		if (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
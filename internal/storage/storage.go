@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/douglasmaicon/Copa-Trick-Fish/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrArquivoNaoEncontrado é retornado quando a chave não existe no storage
+var ErrArquivoNaoEncontrado = errors.New("arquivo não encontrado")
+
+// Storage define as operações de um backend de armazenamento de arquivos
+type Storage interface {
+	// Put grava o conteúdo de r na chave informada (size = -1 se desconhecido)
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get abre o arquivo da chave para leitura
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete remove o arquivo (não dá erro se não existir)
+	Delete(ctx context.Context, key string) error
+	// SignedURL gera uma URL de download válida pelo tempo informado
+	SignedURL(ctx context.Context, key string, expira time.Duration) (string, error)
+}
+
+// Arquivos é o backend configurado para a aplicação
+var Arquivos Storage
+
+// Init cria o backend de armazenamento escolhido em STORAGE_TYPE
+func Init(cfg *config.Config) error {
+	switch cfg.Storage.Type {
+	case "local":
+		local, err := NewLocal(cfg.Storage.LocalPath, cfg.Storage.BucketURL, cfg.Storage.SigningSecret)
+		if err != nil {
+			return err
+		}
+		Arquivos = local
+	case "s3":
+		s3, err := NewS3(S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+		})
+		if err != nil {
+			return err
+		}
+		Arquivos = s3
+	default:
+		return fmt.Errorf("tipo de storage não suportado: %s", cfg.Storage.Type)
+	}
+
+	logrus.Infof("✅ Storage de arquivos inicializado (%s)", cfg.Storage.Type)
+	return nil
+}
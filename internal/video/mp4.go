@@ -0,0 +1,200 @@
+package video
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrFormatoNaoSuportado indica que o arquivo não é um MP4/QuickTime legível
+var ErrFormatoNaoSuportado = errors.New("formato de vídeo não suportado")
+
+// epocaMP4 é a origem dos timestamps MP4/QuickTime (1904-01-01 UTC)
+var epocaMP4 = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Metadados reúne as informações lidas dos boxes moov/mvhd/tkhd
+type Metadados struct {
+	Duracao     time.Duration
+	DataCriacao *time.Time // nil quando o arquivo não informa
+	Largura     int        // já considerando a rotação (vídeo de celular em pé)
+	Altura      int
+}
+
+// LerMetadados lê duração, data de criação e resolução de um MP4/QuickTime sem ffmpeg
+func LerMetadados(r io.ReaderAt, tamanho int64) (*Metadados, error) {
+	var meta Metadados
+	encontrado := false
+
+	err := percorrerBoxes(r, 0, tamanho, func(tipo string, inicio, fim int64) error {
+		if tipo != "moov" {
+			return nil
+		}
+		return percorrerBoxes(r, inicio, fim, func(tipo string, inicio, fim int64) error {
+			switch tipo {
+			case "mvhd":
+				if err := lerMvhd(r, inicio, &meta); err != nil {
+					return err
+				}
+				encontrado = true
+			case "trak":
+				// A resolução vem da primeira trilha de vídeo
+				if meta.Largura == 0 {
+					return lerTrak(r, inicio, fim, &meta)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !encontrado {
+		return nil, ErrFormatoNaoSuportado
+	}
+
+	return &meta, nil
+}
+
+// percorrerBoxes chama fn para cada box entre inicio e fim, com os limites do conteúdo
+func percorrerBoxes(r io.ReaderAt, inicio, fim int64, fn func(tipo string, inicio, fim int64) error) error {
+	cabecalho := make([]byte, 16)
+
+	for pos := inicio; pos+8 <= fim; {
+		if _, err := r.ReadAt(cabecalho[:8], pos); err != nil {
+			return ErrFormatoNaoSuportado
+		}
+
+		tamanho := int64(binary.BigEndian.Uint32(cabecalho[0:4]))
+		tipo := string(cabecalho[4:8])
+		conteudo := pos + 8
+
+		switch tamanho {
+		case 0: // box vai até o fim do arquivo
+			tamanho = fim - pos
+		case 1: // tamanho de 64 bits logo após o cabeçalho
+			if _, err := r.ReadAt(cabecalho[8:16], pos+8); err != nil {
+				return ErrFormatoNaoSuportado
+			}
+			tamanho = int64(binary.BigEndian.Uint64(cabecalho[8:16]))
+			conteudo = pos + 16
+		}
+
+		// Comparar com o que resta evita estouro de pos+tamanho com largesize enorme
+		if tamanho < conteudo-pos || tamanho > fim-pos {
+			return ErrFormatoNaoSuportado
+		}
+
+		if err := fn(tipo, conteudo, pos+tamanho); err != nil {
+			return err
+		}
+
+		pos += tamanho
+	}
+
+	return nil
+}
+
+// lerMvhd decodifica data de criação, timescale e duração do movie header (versões 0 e 1)
+func lerMvhd(r io.ReaderAt, inicio int64, meta *Metadados) error {
+	buf := make([]byte, 32)
+	if _, err := r.ReadAt(buf, inicio); err != nil {
+		return ErrFormatoNaoSuportado
+	}
+
+	var criacao, timescale, duracao uint64
+	if buf[0] == 1 {
+		criacao = binary.BigEndian.Uint64(buf[4:12])
+		timescale = uint64(binary.BigEndian.Uint32(buf[20:24]))
+		duracao = binary.BigEndian.Uint64(buf[24:32])
+	} else {
+		criacao = uint64(binary.BigEndian.Uint32(buf[4:8]))
+		timescale = uint64(binary.BigEndian.Uint32(buf[12:16]))
+		duracao = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+
+	if timescale == 0 {
+		return ErrFormatoNaoSuportado
+	}
+
+	meta.Duracao = time.Duration(float64(duracao) / float64(timescale) * float64(time.Second))
+
+	// Câmeras sem relógio gravam 0 (ou datas absurdas); nesses casos não há data
+	if criacao > 0 {
+		data := epocaMP4.Add(time.Duration(criacao) * time.Second)
+		if data.Year() >= 2000 {
+			meta.DataCriacao = &data
+		}
+	}
+
+	return nil
+}
+
+// lerTrak preenche a resolução se a trilha for de vídeo (hdlr = "vide")
+func lerTrak(r io.ReaderAt, inicio, fim int64, meta *Metadados) error {
+	var largura, altura int
+	ehVideo := false
+
+	err := percorrerBoxes(r, inicio, fim, func(tipo string, inicio, fim int64) error {
+		switch tipo {
+		case "tkhd":
+			l, a, err := lerTkhd(r, inicio)
+			if err != nil {
+				return err
+			}
+			largura, altura = l, a
+		case "mdia":
+			return percorrerBoxes(r, inicio, fim, func(tipo string, inicio, fim int64) error {
+				if tipo != "hdlr" {
+					return nil
+				}
+				handler := make([]byte, 4)
+				if _, err := r.ReadAt(handler, inicio+8); err != nil {
+					return ErrFormatoNaoSuportado
+				}
+				ehVideo = string(handler) == "vide"
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if ehVideo {
+		meta.Largura, meta.Altura = largura, altura
+	}
+
+	return nil
+}
+
+// lerTkhd lê largura e altura (ponto fixo 16.16) e aplica a rotação da matriz
+func lerTkhd(r io.ReaderAt, inicio int64) (int, int, error) {
+	versao := make([]byte, 1)
+	if _, err := r.ReadAt(versao, inicio); err != nil {
+		return 0, 0, ErrFormatoNaoSuportado
+	}
+
+	// Versão 1 usa campos de 64 bits para datas e duração
+	matriz := int64(40)
+	if versao[0] == 1 {
+		matriz = 52
+	}
+
+	buf := make([]byte, 44)
+	if _, err := r.ReadAt(buf, inicio+matriz); err != nil {
+		return 0, 0, ErrFormatoNaoSuportado
+	}
+
+	largura := int(binary.BigEndian.Uint32(buf[36:40]) >> 16)
+	altura := int(binary.BigEndian.Uint32(buf[40:44]) >> 16)
+
+	// Matriz com a = 0 indica rotação de 90° ou 270°
+	if int32(binary.BigEndian.Uint32(buf[0:4])) == 0 {
+		largura, altura = altura, largura
+	}
+
+	return largura, altura, nil
+}
@@ -0,0 +1,273 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// box monta um box MP4 com cabeçalho de 32 bits
+func box(tipo string, partes ...[]byte) []byte {
+	conteudo := bytes.Join(partes, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(conteudo)))
+	return append(append(b, tipo...), conteudo...)
+}
+
+// boxGrande monta um box com tamanho de 64 bits (largesize)
+func boxGrande(tipo string, partes ...[]byte) []byte {
+	conteudo := bytes.Join(partes, nil)
+	b := binary.BigEndian.AppendUint32(nil, 1)
+	b = append(b, tipo...)
+	b = binary.BigEndian.AppendUint64(b, uint64(16+len(conteudo)))
+	return append(b, conteudo...)
+}
+
+// segundosMP4 converte a data para segundos desde 1904
+func segundosMP4(data time.Time) uint64 {
+	return uint64(data.Sub(epocaMP4) / time.Second)
+}
+
+func mvhdV0(criacao uint64, timescale, duracao uint32) []byte {
+	b := make([]byte, 100)
+	binary.BigEndian.PutUint32(b[4:8], uint32(criacao))
+	binary.BigEndian.PutUint32(b[12:16], timescale)
+	binary.BigEndian.PutUint32(b[16:20], duracao)
+	return box("mvhd", b)
+}
+
+func mvhdV1(criacao uint64, timescale uint32, duracao uint64) []byte {
+	b := make([]byte, 112)
+	b[0] = 1
+	binary.BigEndian.PutUint64(b[4:12], criacao)
+	binary.BigEndian.PutUint32(b[20:24], timescale)
+	binary.BigEndian.PutUint64(b[24:32], duracao)
+	return box("mvhd", b)
+}
+
+// tkhd monta o track header; girado usa a matriz de rotação de 90°
+func tkhd(versao byte, largura, altura uint32, girado bool) []byte {
+	matriz := 40
+	if versao == 1 {
+		matriz = 52
+	}
+	b := make([]byte, matriz+44)
+	b[0] = versao
+
+	a, bb, c, d := uint32(0x00010000), uint32(0), uint32(0), uint32(0x00010000)
+	if girado {
+		a, bb, c, d = 0, 0x00010000, 0xFFFF0000, 0
+	}
+	binary.BigEndian.PutUint32(b[matriz:], a)
+	binary.BigEndian.PutUint32(b[matriz+4:], bb)
+	binary.BigEndian.PutUint32(b[matriz+12:], c)
+	binary.BigEndian.PutUint32(b[matriz+16:], d)
+	binary.BigEndian.PutUint32(b[matriz+32:], 0x40000000)
+	binary.BigEndian.PutUint32(b[matriz+36:], largura<<16)
+	binary.BigEndian.PutUint32(b[matriz+40:], altura<<16)
+	return box("tkhd", b)
+}
+
+func trak(handler string, cabecalho []byte) []byte {
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], handler)
+	return box("trak", cabecalho, box("mdia", box("hdlr", hdlr)))
+}
+
+func ftyp() []byte {
+	return box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41"))
+}
+
+func TestLerMetadados(t *testing.T) {
+	gravacao := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	criacao := segundosMP4(gravacao)
+
+	casos := []struct {
+		nome    string
+		arquivo []byte
+		duracao time.Duration
+		data    *time.Time
+		largura int
+		altura  int
+	}{
+		{
+			nome:    "mvhd versão 0",
+			arquivo: bytes.Join([][]byte{ftyp(), box("moov", mvhdV0(criacao, 1000, 12500))}, nil),
+			duracao: 12500 * time.Millisecond,
+			data:    &gravacao,
+		},
+		{
+			nome:    "mvhd versão 1",
+			arquivo: bytes.Join([][]byte{ftyp(), box("moov", mvhdV1(criacao, 600, 600*45))}, nil),
+			duracao: 45 * time.Second,
+			data:    &gravacao,
+		},
+		{
+			nome:    "sem relógio não tem data",
+			arquivo: box("moov", mvhdV0(0, 1000, 3000)),
+			duracao: 3 * time.Second,
+		},
+		{
+			nome: "boxes com largesize",
+			arquivo: bytes.Join([][]byte{
+				ftyp(),
+				boxGrande("mdat", make([]byte, 64)),
+				boxGrande("moov", mvhdV0(criacao, 1000, 20000), trak("vide", tkhd(0, 1920, 1080, false))),
+			}, nil),
+			duracao: 20 * time.Second,
+			data:    &gravacao,
+			largura: 1920,
+			altura:  1080,
+		},
+		{
+			nome: "box com tamanho 0 vai até o fim do arquivo",
+			arquivo: func() []byte {
+				moov := box("moov", mvhdV0(criacao, 1000, 8000))
+				binary.BigEndian.PutUint32(moov[0:4], 0)
+				return append(ftyp(), moov...)
+			}(),
+			duracao: 8 * time.Second,
+			data:    &gravacao,
+		},
+		{
+			nome:    "resolução da trilha de vídeo",
+			arquivo: box("moov", mvhdV0(criacao, 1000, 5000), trak("vide", tkhd(0, 1280, 720, false))),
+			duracao: 5 * time.Second,
+			data:    &gravacao,
+			largura: 1280,
+			altura:  720,
+		},
+		{
+			nome:    "celular em pé: rotação de 90° troca largura e altura",
+			arquivo: box("moov", mvhdV0(criacao, 1000, 5000), trak("vide", tkhd(0, 1920, 1080, true))),
+			duracao: 5 * time.Second,
+			data:    &gravacao,
+			largura: 1080,
+			altura:  1920,
+		},
+		{
+			nome:    "tkhd versão 1",
+			arquivo: box("moov", mvhdV1(criacao, 1000, 5000), trak("vide", tkhd(1, 640, 480, false))),
+			duracao: 5 * time.Second,
+			data:    &gravacao,
+			largura: 640,
+			altura:  480,
+		},
+		{
+			nome: "trilha de áudio antes da de vídeo é ignorada",
+			arquivo: box("moov", mvhdV0(criacao, 1000, 5000),
+				trak("soun", tkhd(0, 0, 0, false)), trak("vide", tkhd(0, 1280, 720, false))),
+			duracao: 5 * time.Second,
+			data:    &gravacao,
+			largura: 1280,
+			altura:  720,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			meta, err := LerMetadados(bytes.NewReader(caso.arquivo), int64(len(caso.arquivo)))
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+
+			if meta.Duracao != caso.duracao {
+				t.Errorf("duração esperada %v, obtida %v", caso.duracao, meta.Duracao)
+			}
+			switch {
+			case caso.data == nil && meta.DataCriacao != nil:
+				t.Errorf("data esperada nil, obtida %v", *meta.DataCriacao)
+			case caso.data != nil && (meta.DataCriacao == nil || !meta.DataCriacao.Equal(*caso.data)):
+				t.Errorf("data esperada %v, obtida %v", *caso.data, meta.DataCriacao)
+			}
+			if meta.Largura != caso.largura || meta.Altura != caso.altura {
+				t.Errorf("resolução esperada %dx%d, obtida %dx%d", caso.largura, caso.altura, meta.Largura, meta.Altura)
+			}
+		})
+	}
+}
+
+func TestLerMetadadosInvalido(t *testing.T) {
+	valido := box("moov", mvhdV0(1, 1000, 5000), trak("vide", tkhd(0, 1280, 720, false)))
+
+	casos := []struct {
+		nome    string
+		arquivo []byte
+	}{
+		{nome: "arquivo vazio", arquivo: nil},
+		{nome: "texto qualquer", arquivo: []byte("isto não é um vídeo, é só um texto qualquer")},
+		{nome: "sem moov", arquivo: ftyp()},
+		{nome: "moov truncado", arquivo: valido[:len(valido)-30]},
+		{nome: "mvhd truncado", arquivo: box("moov", box("mvhd", make([]byte, 10)))},
+		{nome: "timescale zero", arquivo: box("moov", mvhdV0(1, 0, 5000))},
+		{
+			nome: "box menor que o cabeçalho",
+			arquivo: func() []byte {
+				b := box("moov", mvhdV0(1, 1000, 5000))
+				binary.BigEndian.PutUint32(b[0:4], 4)
+				return b
+			}(),
+		},
+		{
+			nome: "largesize que estoura int64",
+			arquivo: func() []byte {
+				b := boxGrande("moov", mvhdV0(1, 1000, 5000))
+				binary.BigEndian.PutUint64(b[8:16], 0xFFFFFFFFFFFFFFF0)
+				return b
+			}(),
+		},
+		{
+			nome: "largesize maior que o arquivo",
+			arquivo: func() []byte {
+				b := boxGrande("moov", mvhdV0(1, 1000, 5000))
+				binary.BigEndian.PutUint64(b[8:16], 0x7FFFFFFFFFFFFFF0)
+				return b
+			}(),
+		},
+		{
+			nome: "largesize aninhado que estouraria a posição",
+			arquivo: func() []byte {
+				interno := boxGrande("trak", make([]byte, 8))
+				binary.BigEndian.PutUint64(interno[8:16], 0x7FFFFFFFFFFFFFFF)
+				return box("moov", mvhdV0(1, 1000, 5000), interno)
+			}(),
+		},
+		{nome: "tkhd truncado", arquivo: box("moov", mvhdV0(1, 1000, 5000), box("trak", box("tkhd", make([]byte, 20))))},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			meta, err := LerMetadados(bytes.NewReader(caso.arquivo), int64(len(caso.arquivo)))
+			if !errors.Is(err, ErrFormatoNaoSuportado) {
+				t.Errorf("esperado ErrFormatoNaoSuportado, obtido %v (%+v)", err, meta)
+			}
+		})
+	}
+}
+
+// Bytes aleatórios e arquivos válidos corrompidos devem dar erro ou metadados, nunca pânico
+func TestLerMetadadosLixo(t *testing.T) {
+	aleatorio := rand.New(rand.NewSource(1))
+	valido := bytes.Join([][]byte{
+		ftyp(),
+		boxGrande("moov", mvhdV1(1, 1000, 5000), trak("vide", tkhd(1, 1280, 720, true))),
+	}, nil)
+
+	for i := 0; i < 2000; i++ {
+		var arquivo []byte
+		if i%2 == 0 {
+			arquivo = make([]byte, aleatorio.Intn(256))
+			aleatorio.Read(arquivo)
+		} else {
+			arquivo = append([]byte(nil), valido...)
+			for j := 0; j < 1+aleatorio.Intn(4); j++ {
+				arquivo[aleatorio.Intn(len(arquivo))] = byte(aleatorio.Intn(256))
+			}
+			arquivo = arquivo[:aleatorio.Intn(len(arquivo)+1)]
+		}
+
+		LerMetadados(bytes.NewReader(arquivo), int64(len(arquivo)))
+	}
+}